@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/pkg/money"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const catalogUsage = `usage: catalog <command>
+
+commands:
+  import -file products.csv -seller ID  import products from a csv file
+
+The csv needs a header row with the columns
+  name,description,category,price,stock,image_url
+and optionally currency, which defaults to payments.currency, weight in
+grams and length, width and height in millimetres. Prices are decimals
+such as 19.99. Unknown categories are created. The import is all or
+nothing.`
+
+var importColumns = []string{"name", "description", "category", "price", "stock", "image_url"}
+
+func runCatalog(args []string) {
+	if len(args) < 1 || args[0] != "import" {
+		fmt.Println(catalogUsage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("catalog import", flag.ExitOnError)
+	file := fs.String("file", "", "csv file to import")
+	sellerId := fs.Uint("seller", 0, "id of the seller owning the products")
+	fs.Parse(args[1:])
+
+	if *file == "" || *sellerId == 0 {
+		log.Fatalln("catalog import: -file and -seller are required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("catalog import: %v\n", err)
+	}
+	defer f.Close()
+
+	cfg := loadConfig()
+
+	rows, err := readProductRows(f, cfg.Payments.Currency)
+	if err != nil {
+		log.Fatalf("catalog import: %v\n", err)
+	}
+
+	ctx := context.Background()
+	db := connect(cfg)
+
+	err = repository.NewUnitOfWork(db).Do(ctx, func(repos repository.Repositories) error {
+		seller, err := repos.User.FindUserById(ctx, *sellerId)
+		if err != nil {
+			return err
+		}
+
+		if seller.UserType != domain.SELLER {
+			return fmt.Errorf("user %d is not a seller", seller.ID)
+		}
+
+		categories := map[string]uint{}
+		for _, row := range rows {
+			id, ok := categories[row.category]
+			if !ok {
+				category, err := repos.Catalog.FindCategoryByName(ctx, row.category)
+				if errors.Is(err, domain.ErrCategoryNotFound) {
+					category = &domain.Category{Name: row.category}
+					err = repos.Catalog.CreateCategory(ctx, category)
+				}
+				if err != nil {
+					return err
+				}
+
+				id = category.ID
+				categories[row.category] = id
+			}
+
+			product := row.product
+			product.CategoryId = id
+			product.UserId = int(seller.ID)
+
+			if err := repos.Catalog.CreateProduct(ctx, &product); err != nil {
+				return fmt.Errorf("line %d: %w", row.line, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("catalog import: %v\n", err)
+	}
+
+	fmt.Printf("imported %d products\n", len(rows))
+}
+
+type productRow struct {
+	line     int
+	category string
+	product  domain.Product
+}
+
+func readProductRows(r io.Reader, currency string) ([]productRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	for _, c := range importColumns {
+		if _, ok := index[c]; !ok {
+			return nil, fmt.Errorf("missing column %q", c)
+		}
+	}
+
+	var rows []productRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		col := func(name string) string {
+			return strings.TrimSpace(record[index[name]])
+		}
+
+		rowCurrency := currency
+		if _, ok := index["currency"]; ok && col("currency") != "" {
+			rowCurrency = strings.ToUpper(col("currency"))
+		}
+
+		if !money.Known(rowCurrency) {
+			return nil, fmt.Errorf("line %d: unknown currency %q", line, rowCurrency)
+		}
+
+		// prices with more decimals than the currency has are rejected, not
+		// rounded
+		price, err := money.Parse(col("price"), rowCurrency)
+		if err != nil || price.Amount < 0 {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, col("price"))
+		}
+
+		stock, err := strconv.ParseUint(col("stock"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid stock %q", line, col("stock"))
+		}
+
+		var size [4]uint
+		for i, name := range []string{"weight", "length", "width", "height"} {
+			if _, ok := index[name]; !ok || col(name) == "" {
+				continue
+			}
+
+			n, err := strconv.ParseUint(col(name), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", line, name, col(name))
+			}
+			size[i] = uint(n)
+		}
+
+		if col("name") == "" || col("category") == "" {
+			return nil, fmt.Errorf("line %d: name and category are required", line)
+		}
+
+		rows = append(rows, productRow{
+			line:     line,
+			category: col("category"),
+			product: domain.Product{
+				Name:        col("name"),
+				Description: col("description"),
+				ImageUrl:    col("image_url"),
+				Price:       price,
+				Stock:       uint(stock),
+				Weight:      size[0],
+				Length:      size[1],
+				Width:       size[2],
+				Height:      size[3],
+			},
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+const configUsage = `usage: config <command>
+
+commands:
+  print [--redacted]  print the effective configuration as YAML`
+
+func runConfig(args []string) {
+	if len(args) < 1 || args[0] != "print" {
+		fmt.Println(configUsage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	redacted := fs.Bool("redacted", false, "mask secrets such as the database dsn and api keys")
+	fs.Parse(args[1:])
+
+	cfg := loadConfig()
+	if *redacted {
+		cfg = cfg.Redacted()
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		log.Fatalf("config print: %v\n", err)
+	}
+
+	fmt.Print(out)
+}
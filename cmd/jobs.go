@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-ecommerce-app/internal/jobs"
+	"log"
+	"os"
+)
+
+const jobsUsage = `usage: jobs <command>
+
+commands:
+  list        list the available jobs
+  run <name>  run a job once`
+
+func runJobs(args []string) {
+	if len(args) < 1 {
+		fmt.Println(jobsUsage)
+		os.Exit(2)
+	}
+
+	cfg := loadConfig()
+
+	switch args[0] {
+	case "list":
+		for _, j := range jobs.Registry(nil, cfg) {
+			fmt.Printf("  %-12s %s\n", j.Name, j.Description)
+		}
+
+	case "run":
+		if len(args) != 2 {
+			log.Fatalln("jobs run: a job name is required")
+		}
+
+		job, ok := jobs.Find(jobs.Registry(connect(cfg), cfg), args[1])
+		if !ok {
+			log.Fatalf("jobs run: unknown job %q\n", args[1])
+		}
+
+		if err := job.Run(context.Background()); err != nil {
+			log.Fatalf("jobs run %s: %v\n", job.Name, err)
+		}
+
+		fmt.Printf("job %s finished\n", job.Name)
+
+	default:
+		fmt.Println(jobsUsage)
+		os.Exit(2)
+	}
+}
@@ -1,17 +1,90 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"go-ecommerce-app/config"
-	"go-ecommerce-app/internal/api"
+	"go-ecommerce-app/internal/database"
+	"go-ecommerce-app/internal/logging"
 	"log"
+	"os"
+
+	"gorm.io/gorm"
 )
 
+type command struct {
+	name  string
+	usage string
+	run   func(args []string)
+}
+
+var commands = []command{
+	{"serve", "start the http server (default)", runServe},
+	{"migrate", "manage database migrations (up, down, status, create)", runMigrate},
+	{"seed", "insert demo users, categories and products", runSeed},
+	{"user", "manage users (create-admin, reset-password)", runUser},
+	{"catalog", "manage the catalog (import)", runCatalog},
+	{"rates", "manage currency exchange rates (list, import)", runRates},
+	{"jobs", "run background jobs by hand (list, run <name>)", runJobs},
+	{"config", "inspect the effective configuration (print)", runConfig},
+}
+
+// globalFlags are the configuration overrides given before the command.
+var globalFlags = flag.NewFlagSet("app", flag.ExitOnError)
+
+var configFlags = config.RegisterFlags(globalFlags)
+
 func main() {
-	cfg, err := config.SetupEnv()
+	globalFlags.Usage = usage
+	globalFlags.Parse(os.Args[1:])
+
+	// no command keeps the old behaviour of starting the server
+	if globalFlags.NArg() == 0 {
+		runServe(nil)
+		return
+	}
+
+	name, args := globalFlags.Arg(0), globalFlags.Args()[1:]
+
+	for _, c := range commands {
+		if c.name == name {
+			c.run(args)
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Println("usage: [flags] <command> [arguments]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Printf("  %-10s %s\n", c.name, c.usage)
+	}
+
+	fmt.Println("\nflags override the config file and environment:")
+	globalFlags.PrintDefaults()
+}
+
+func loadConfig() config.AppConfig {
+	cfg, err := config.Load(configFlags)
 
 	if err != nil {
 		log.Fatalf("config file is not loaded %v\n", err)
 	}
 
-	api.StartServer(cfg)
+	logging.Setup(cfg.Log)
+
+	return cfg
+}
+
+func connect(cfg config.AppConfig) *gorm.DB {
+	db, err := database.Connect(cfg.Database)
+
+	if err != nil {
+		log.Fatalf("database connection error %v\n", err)
+	}
+
+	return db
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-ecommerce-app/internal/database/migrations"
+	"log"
+	"os"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up               apply all pending migrations
+  down [-steps N]  revert the last N applied migrations (default 1)
+  status           list migrations and whether they are applied
+  create <name>    write a new empty up/down migration pair`
+
+func runMigrate(args []string) {
+	if len(args) < 1 {
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+
+	cmd, args := args[0], args[1:]
+
+	// create only writes files, it doesn't need a database
+	if cmd == "create" {
+		fs := flag.NewFlagSet("migrate create", flag.ExitOnError)
+		dir := fs.String("dir", migrations.SourceDir, "directory to write the migration files to")
+		fs.Parse(args)
+
+		if fs.NArg() != 1 {
+			log.Fatalln("migrate create: a migration name is required")
+		}
+
+		m, err := migrations.New(nil)
+		if err != nil {
+			log.Fatalf("migrate create: %v\n", err)
+		}
+
+		up, down, err := m.Create(*dir, fs.Arg(0))
+		if err != nil {
+			log.Fatalf("migrate create: %v\n", err)
+		}
+
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+		return
+	}
+
+	db := connect(loadConfig())
+
+	m, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("migrate: %v\n", err)
+	}
+
+	switch cmd {
+	case "up":
+		applied, err := m.Up()
+		for _, mig := range applied {
+			fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate up: %v\n", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+		fs.Parse(args)
+
+		reverted, err := m.Down(*steps)
+		for _, mig := range reverted {
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate down: %v\n", err)
+		}
+
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			log.Fatalf("migrate status: %v\n", err)
+		}
+
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = "applied " + st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", st.Version, st.Name, applied)
+		}
+
+	default:
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+}
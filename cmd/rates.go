@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"go-ecommerce-app/pkg/money"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+const ratesUsage = `usage: rates <command>
+
+commands:
+  list                 print the exchange rates
+  import -file F.csv   add or update exchange rates from a csv file
+
+The csv needs a header row with the columns
+  currency,rate
+where rate is how many units of the currency one unit of payments.currency
+buys, e.g. USD,1.0842. Currencies missing from the file keep their rate.`
+
+func runRates(args []string) {
+	if len(args) < 1 {
+		fmt.Println(ratesUsage)
+		os.Exit(2)
+	}
+
+	cmd, args := args[0], args[1:]
+
+	switch cmd {
+	case "list":
+		svc := newCurrencyService()
+
+		rates, err := svc.GetRates(context.Background())
+		if err != nil {
+			log.Fatalf("rates list: %v\n", err)
+		}
+
+		fmt.Printf("base currency %s\n", svc.Config.Payments.Currency)
+		for _, r := range rates {
+			fmt.Printf("  %s %s (updated %s)\n", r.Currency, r.Rate, r.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+
+	case "import":
+		fs := flag.NewFlagSet("rates import", flag.ExitOnError)
+		file := fs.String("file", "", "csv file to import")
+		fs.Parse(args)
+
+		if *file == "" {
+			log.Fatalln("rates import: -file is required")
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("rates import: %v\n", err)
+		}
+		defer f.Close()
+
+		input, err := readRateRows(f)
+		if err != nil {
+			log.Fatalf("rates import: %v\n", err)
+		}
+
+		if _, err := newCurrencyService().SetRates(context.Background(), input); err != nil {
+			log.Fatalf("rates import: %v\n", err)
+		}
+
+		fmt.Printf("imported %d rates\n", len(input.Rates))
+
+	default:
+		fmt.Println(ratesUsage)
+		os.Exit(2)
+	}
+}
+
+func readRateRows(r io.Reader) (dto.CurrencyRatesInput, error) {
+	input := dto.CurrencyRatesInput{Rates: map[string]money.Rate{}}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return input, fmt.Errorf("reading header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	for _, c := range []string{"currency", "rate"} {
+		if _, ok := index[c]; !ok {
+			return input, fmt.Errorf("missing column %q", c)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return input, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		code := strings.ToUpper(strings.TrimSpace(record[index["currency"]]))
+		if _, ok := input.Rates[code]; ok {
+			return input, fmt.Errorf("line %d: duplicate currency %q", line, code)
+		}
+
+		rate, err := money.ParseRate(record[index["rate"]])
+		if err != nil {
+			return input, fmt.Errorf("line %d: invalid rate %q", line, record[index["rate"]])
+		}
+
+		input.Rates[code] = rate
+	}
+
+	if len(input.Rates) == 0 {
+		return input, fmt.Errorf("no rates in file")
+	}
+
+	return input, nil
+}
+
+func newCurrencyService() service.CurrencyService {
+	cfg := loadConfig()
+
+	return service.CurrencyService{
+		Repo:   repository.NewCurrencyRepository(connect(cfg)),
+		Auth:   helper.SetupAuth(cfg.Auth),
+		Config: cfg,
+	}
+}
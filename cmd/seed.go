@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/pkg/money"
+	"log"
+)
+
+const seedPassword = "password123"
+
+var seedUsers = []domain.User{
+	{Email: "admin@example.com", FirstName: "Ada", LastName: "Admin", Phone: "+351910000001", UserType: domain.ADMIN},
+	{Email: "seller@example.com", FirstName: "Sam", LastName: "Seller", Phone: "+351910000002", UserType: domain.SELLER},
+	{Email: "buyer@example.com", FirstName: "Bea", LastName: "Buyer", Phone: "+351910000003", UserType: domain.BUYER},
+}
+
+var seedCategories = []domain.Category{
+	{Name: "Electronics", DisplayOrder: 1},
+	{Name: "Clothing", DisplayOrder: 2},
+	{Name: "Home & Kitchen", DisplayOrder: 3},
+	{Name: "Books", DisplayOrder: 4},
+}
+
+var seedProducts = []struct {
+	category string
+	product  domain.Product
+}{
+	{"Electronics", domain.Product{Name: "Wireless Headphones", Description: "Over-ear, noise cancelling", Price: money.Money{Amount: 12999}, Stock: 25}},
+	{"Electronics", domain.Product{Name: "USB-C Charger 65W", Description: "GaN wall charger", Price: money.Money{Amount: 3990}, Stock: 100}},
+	{"Clothing", domain.Product{Name: "Cotton T-Shirt", Description: "100% organic cotton", Price: money.Money{Amount: 1950}, Stock: 60}},
+	{"Home & Kitchen", domain.Product{Name: "Chef Knife", Description: "20cm stainless steel", Price: money.Money{Amount: 4900}, Stock: 15}},
+	{"Books", domain.Product{Name: "The Go Programming Language", Description: "Donovan & Kernighan", Price: money.Money{Amount: 3499}, Stock: 30}},
+}
+
+// runSeed inserts demo data. It is idempotent: records that already exist
+// (by email or name) are left untouched.
+func runSeed(args []string) {
+	cfg := loadConfig()
+	ctx := context.Background()
+	db := connect(cfg)
+	auth := helper.SetupAuth(cfg.Auth)
+
+	hPassword, err := auth.CreateHashPassword(seedPassword)
+	if err != nil {
+		log.Fatalf("seed: %v\n", err)
+	}
+
+	err = repository.NewUnitOfWork(db).Do(ctx, func(repos repository.Repositories) error {
+		var seller domain.User
+
+		for _, u := range seedUsers {
+			user, err := repos.User.FindUser(ctx, u.Email)
+			if errors.Is(err, domain.ErrUserNotFound) {
+				u.Password = hPassword
+				u.Verified = true
+				user, err = repos.User.CreateUser(ctx, u)
+				if err == nil {
+					fmt.Printf("created user %s / %s\n", u.Email, seedPassword)
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			if user.UserType == domain.SELLER {
+				seller = user
+			}
+		}
+
+		categories := map[string]uint{}
+		for _, c := range seedCategories {
+			category, err := repos.Catalog.FindCategoryByName(ctx, c.Name)
+			if errors.Is(err, domain.ErrCategoryNotFound) {
+				category = &c
+				err = repos.Catalog.CreateCategory(ctx, category)
+				if err == nil {
+					fmt.Printf("created category %s\n", c.Name)
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			categories[c.Name] = category.ID
+		}
+
+		existing, err := repos.Catalog.FindSellerProducts(ctx, seller.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(existing) > 0 {
+			return nil
+		}
+
+		for _, p := range seedProducts {
+			product := p.product
+			product.CategoryId = categories[p.category]
+			product.UserId = int(seller.ID)
+			product.Price.Currency = cfg.Payments.Currency
+
+			if err := repos.Catalog.CreateProduct(ctx, &product); err != nil {
+				return err
+			}
+			fmt.Printf("created product %s\n", product.Name)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("seed: %v\n", err)
+	}
+
+	fmt.Println("seed finished")
+}
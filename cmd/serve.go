@@ -0,0 +1,14 @@
+package main
+
+import (
+	"go-ecommerce-app/internal/api"
+	"log"
+)
+
+func runServe(args []string) {
+	if err := api.StartServer(loadConfig()); err != nil {
+		log.Fatalf("server error: %v\n", err)
+	}
+
+	log.Println("server stopped")
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// passwordEnv holds the password for scripts; a flag would show in ps and
+// the shell history.
+const passwordEnv = "USER_PASSWORD"
+
+const userUsage = `usage: user <command>
+
+commands:
+  create-admin -email E [-phone N]      create a verified admin account
+  reset-password -email E [-generate]   set a new password, or a generated one
+
+The password is read from $USER_PASSWORD if set, else asked for on a
+terminal, else read from the first line of stdin, e.g. < password.txt.`
+
+func runUser(args []string) {
+	if len(args) < 1 {
+		fmt.Println(userUsage)
+		os.Exit(2)
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("user "+cmd, flag.ExitOnError)
+	email := fs.String("email", "", "email of the user")
+
+	switch cmd {
+	case "create-admin":
+		phone := fs.String("phone", "", "phone number of the user")
+		fs.Parse(args)
+
+		if *email == "" {
+			log.Fatalln("user create-admin: -email is required")
+		}
+
+		password, err := readPassword()
+		if err != nil {
+			log.Fatalf("user create-admin: %v\n", err)
+		}
+
+		user, err := newUserService().CreateAdmin(context.Background(), *email, password, *phone)
+		if err != nil {
+			log.Fatalf("user create-admin: %v\n", err)
+		}
+
+		fmt.Printf("created admin %s (id %d)\n", user.Email, user.ID)
+
+	case "reset-password":
+		generate := fs.Bool("generate", false, "generate a random password and print it")
+		fs.Parse(args)
+
+		if *email == "" {
+			log.Fatalln("user reset-password: -email is required")
+		}
+
+		var password string
+		var err error
+		if *generate {
+			password, err = helper.RandomString(12)
+		} else {
+			password, err = readPassword()
+		}
+		if err != nil {
+			log.Fatalf("user reset-password: %v\n", err)
+		}
+
+		if err := newUserService().ResetPassword(context.Background(), *email, password); err != nil {
+			log.Fatalf("user reset-password: %v\n", err)
+		}
+
+		if *generate {
+			fmt.Printf("password for %s reset to %s\n", *email, password)
+		} else {
+			fmt.Printf("password for %s reset\n", *email)
+		}
+
+	default:
+		fmt.Println(userUsage)
+		os.Exit(2)
+	}
+}
+
+// readPassword gets the password from the environment, a prompt on the
+// terminal, typed twice, or the first line of stdin, in that order.
+func readPassword() (string, error) {
+	if password, ok := os.LookupEnv(passwordEnv); ok {
+		if password == "" {
+			return "", errors.New("$" + passwordEnv + " is empty")
+		}
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// a last line without a newline is still a password
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", errors.New("no password on stdin")
+		}
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprint(os.Stderr, "Repeat password: ")
+	again, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	if string(password) != string(again) {
+		return "", errors.New("passwords don't match")
+	}
+	if len(password) == 0 {
+		return "", errors.New("password is required")
+	}
+
+	return string(password), nil
+}
+
+func newUserService() service.UserService {
+	cfg := loadConfig()
+	db := connect(cfg)
+
+	return service.UserService{
+		Repo:   repository.NewUserRepository(db),
+		Tx:     repository.NewUnitOfWork(db),
+		Auth:   helper.SetupAuth(cfg.Auth),
+		Config: cfg,
+	}
+}
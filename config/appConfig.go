@@ -2,43 +2,349 @@ package config
 
 import (
 	"errors"
+	"flag"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// AppConfig is assembled from, in increasing order of precedence: defaults,
+// a YAML file, environment variables and command line flags. Every setting
+// has a dotted key (e.g. "database.max_open_conns") built from the yaml
+// tags, which is also the name of its flag. Fields tagged secret:"true" are
+// redacted by Redacted.
 type AppConfig struct {
-	ServerPort        string
-	Dsn               string
-	AppSecret         string
-	TwilioAccountSid  string
-	TwilioAuthToken   string
-	TwilioPhoneNumber string
+	Env           string             `yaml:"env" env:"APP_ENV"`
+	Log           LogConfig          `yaml:"log"`
+	Tracing       TracingConfig      `yaml:"tracing"`
+	Server        ServerConfig       `yaml:"server"`
+	Database      DatabaseConfig     `yaml:"database"`
+	Auth          AuthConfig         `yaml:"auth"`
+	Notifications NotificationConfig `yaml:"notifications"`
+	Payments      PaymentConfig      `yaml:"payments"`
+	Checkout      CheckoutConfig     `yaml:"checkout"`
+	Tax           TaxConfig          `yaml:"tax"`
+	Shipping      ShippingConfig     `yaml:"shipping"`
+	Media         MediaConfig        `yaml:"media"`
+	RateLimits    RateLimitConfig    `yaml:"rate_limits"`
 }
 
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig selects where spans are exported: "none" disables tracing,
+// "stdout" prints them for local use and "otlp" sends them over OTLP/HTTP to
+// Endpoint (or the standard OTEL_EXPORTER_OTLP_* variables when empty).
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type ServerConfig struct {
+	Port            string        `yaml:"port" env:"HTTP_PORT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	BodyLimit       int           `yaml:"body_limit" env:"HTTP_BODY_LIMIT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"`
+	HealthTimeout   time.Duration `yaml:"health_timeout" env:"HTTP_HEALTH_TIMEOUT"`
+}
+
+type DatabaseConfig struct {
+	Dsn             string        `yaml:"dsn" env:"DSN" secret:"true"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+}
+
+type AuthConfig struct {
+	Secret   string        `yaml:"secret" env:"APP_SECRET" secret:"true"`
+	TokenTTL time.Duration `yaml:"token_ttl" env:"AUTH_TOKEN_TTL"`
+}
+
+type NotificationConfig struct {
+	Twilio TwilioConfig `yaml:"twilio"`
+	Sms    SmsConfig    `yaml:"sms"`
+}
+
+// SmsConfig guards what we pay for. Phone numbers are normalized to E.164,
+// taking DefaultCountry for numbers given without a country code, and only
+// numbers from AllowedCountries (ISO 3166-1 alpha-2) are accepted. Numbers
+// starting with one of BlockedPrefixes, or that are premium-rate, never get
+// a message.
+type SmsConfig struct {
+	DefaultCountry     string   `yaml:"default_country" env:"SMS_DEFAULT_COUNTRY"`
+	AllowedCountries   []string `yaml:"allowed_countries" env:"SMS_ALLOWED_COUNTRIES"`
+	BlockedPrefixes    []string `yaml:"blocked_prefixes" env:"SMS_BLOCKED_PREFIXES"`
+	MaxPerNumberPerDay int      `yaml:"max_per_number_per_day" env:"SMS_MAX_PER_NUMBER_PER_DAY"`
+	MaxPerHour         int      `yaml:"max_per_hour" env:"SMS_MAX_PER_HOUR"`
+}
+
+type TwilioConfig struct {
+	AccountSid  string `yaml:"account_sid" env:"TWILIO_ACCOUNT_SID"`
+	AuthToken   string `yaml:"auth_token" env:"TWILIO_AUTH_TOKEN" secret:"true"`
+	PhoneNumber string `yaml:"phone_number" env:"TWILIO_PHONE_NUMBER"`
+}
+
+// PaymentConfig selects the payment provider: "stripe", "test" (payments
+// are created locally and completed by posting signed events to the payment
+// webhook, for development) or "" to disable checkout. Currency is the
+// currency products are priced and orders are charged in.
+type PaymentConfig struct {
+	Provider      string `yaml:"provider" env:"PAYMENT_PROVIDER"`
+	SecretKey     string `yaml:"secret_key" env:"PAYMENT_SECRET_KEY" secret:"true"`
+	WebhookSecret string `yaml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET" secret:"true"`
+	Currency      string `yaml:"currency" env:"PAYMENT_CURRENCY"`
+}
+
+// CheckoutConfig controls stock reservations: checkout holds the stock of an
+// order for ReservationTTL, and a sweeper running every SweepInterval
+// releases the stock of orders that were not paid in time.
+type CheckoutConfig struct {
+	ReservationTTL time.Duration `yaml:"reservation_ttl" env:"CHECKOUT_RESERVATION_TTL"`
+	SweepInterval  time.Duration `yaml:"sweep_interval" env:"CHECKOUT_SWEEP_INTERVAL"`
+}
+
+// TaxConfig selects how tax is calculated at checkout: "local" looks rates
+// up in the tax_rates table and "none" charges no tax. PricesIncludeTax
+// says whether product prices already include tax, in which case tax is
+// taken out of them rather than added on top.
+type TaxConfig struct {
+	Provider         string `yaml:"provider" env:"TAX_PROVIDER"`
+	PricesIncludeTax bool   `yaml:"prices_include_tax" env:"TAX_PRICES_INCLUDE_TAX"`
+}
+
+// ShippingConfig tunes shipping quotes. Parcels are charged by the larger
+// of their weight and their volumetric weight, the volume in cubic
+// centimetres divided by VolumetricDivisor; zero charges by weight only.
+type ShippingConfig struct {
+	VolumetricDivisor int `yaml:"volumetric_divisor" env:"SHIPPING_VOLUMETRIC_DIVISOR"`
+}
+
+// MediaConfig controls product image uploads. Storage is "local" (files
+// under Local.Dir, served by the app from Local.BaseUrl) or "s3" (any
+// S3-compatible bucket). Images must be between MinDimension and
+// MaxDimension pixels on each side and at most MaxFileSize bytes; a
+// thumbnail ThumbnailSize pixels on its longest side is stored next to each
+// one. Stored files no image refers to are deleted once older than
+// OrphanAge, checked every CleanupInterval.
+type MediaConfig struct {
+	Storage          string             `yaml:"storage" env:"MEDIA_STORAGE"`
+	MaxFileSize      int                `yaml:"max_file_size" env:"MEDIA_MAX_FILE_SIZE"`
+	MaxFiles         int                `yaml:"max_files" env:"MEDIA_MAX_FILES"`
+	MaxProductImages int                `yaml:"max_product_images" env:"MEDIA_MAX_PRODUCT_IMAGES"`
+	MinDimension     int                `yaml:"min_dimension" env:"MEDIA_MIN_DIMENSION"`
+	MaxDimension     int                `yaml:"max_dimension" env:"MEDIA_MAX_DIMENSION"`
+	ThumbnailSize    int                `yaml:"thumbnail_size" env:"MEDIA_THUMBNAIL_SIZE"`
+	OrphanAge        time.Duration      `yaml:"orphan_age" env:"MEDIA_ORPHAN_AGE"`
+	CleanupInterval  time.Duration      `yaml:"cleanup_interval" env:"MEDIA_CLEANUP_INTERVAL"`
+	Local            LocalStorageConfig `yaml:"local"`
+	S3               S3StorageConfig    `yaml:"s3"`
+}
+
+type LocalStorageConfig struct {
+	Dir     string `yaml:"dir" env:"MEDIA_LOCAL_DIR"`
+	BaseUrl string `yaml:"base_url" env:"MEDIA_BASE_URL"`
+}
+
+// S3StorageConfig points at a bucket. Endpoint is empty for AWS or the URL
+// of a compatible server such as MinIO, which usually needs PathStyle.
+// PublicUrl is the prefix files are served from, e.g. a CDN; it defaults to
+// the bucket URL.
+type S3StorageConfig struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Region    string `yaml:"region" env:"S3_REGION"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY" secret:"true"`
+	PublicUrl string `yaml:"public_url" env:"S3_PUBLIC_URL"`
+	PathStyle bool   `yaml:"path_style" env:"S3_PATH_STYLE"`
+}
+
+// RateLimitConfig holds the token bucket policies. Requests and Window are
+// the default policy applied to every route per client IP; Auth covers login
+// and code verification, and Sms the routes that send a text message.
+type RateLimitConfig struct {
+	Enabled  bool            `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Requests int             `yaml:"requests" env:"RATE_LIMIT_REQUESTS"`
+	Window   time.Duration   `yaml:"window" env:"RATE_LIMIT_WINDOW"`
+	Auth     RateLimitPolicy `yaml:"auth"`
+	Sms      RateLimitPolicy `yaml:"sms"`
+}
+
+// RateLimitPolicy allows bursts of up to Requests, refilled at Requests per
+// Window.
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+// Addr is the listen address for the http server. The port may be given
+// with or without the leading colon.
+func (c ServerConfig) Addr() string {
+	if strings.Contains(c.Port, ":") {
+		return c.Port
+	}
+	return ":" + c.Port
+}
+
+func (c TwilioConfig) Configured() bool {
+	return c.AccountSid != "" || c.AuthToken != "" || c.PhoneNumber != ""
+}
+
+func Defaults() AppConfig {
+	return AppConfig{
+		Env: "prod",
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "go-ecommerce-app",
+			SampleRatio: 1,
+		},
+		Server: ServerConfig{
+			Port:            "9000",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			BodyLimit:       16 * 1024 * 1024,
+			ShutdownTimeout: 20 * time.Second,
+			HealthTimeout:   2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			TokenTTL: 30 * 24 * time.Hour,
+		},
+		Notifications: NotificationConfig{
+			Sms: SmsConfig{
+				DefaultCountry:   "PT",
+				AllowedCountries: []string{"PT"},
+				// satellite and international networks, billed at premium rates
+				BlockedPrefixes:    []string{"+870", "+881", "+882", "+883"},
+				MaxPerNumberPerDay: 5,
+				MaxPerHour:         500,
+			},
+		},
+		Payments: PaymentConfig{
+			Currency: "EUR",
+		},
+		Checkout: CheckoutConfig{
+			ReservationTTL: 15 * time.Minute,
+			SweepInterval:  time.Minute,
+		},
+		Tax: TaxConfig{
+			Provider:         "local",
+			PricesIncludeTax: true,
+		},
+		Shipping: ShippingConfig{
+			VolumetricDivisor: 5000,
+		},
+		Media: MediaConfig{
+			Storage:          "local",
+			MaxFileSize:      5 * 1024 * 1024,
+			MaxFiles:         10,
+			MaxProductImages: 20,
+			MinDimension:     100,
+			MaxDimension:     6000,
+			ThumbnailSize:    320,
+			OrphanAge:        24 * time.Hour,
+			CleanupInterval:  time.Hour,
+			Local: LocalStorageConfig{
+				Dir:     "media",
+				BaseUrl: "/media",
+			},
+			S3: S3StorageConfig{
+				Region: "us-east-1",
+			},
+		},
+		RateLimits: RateLimitConfig{
+			Enabled:  true,
+			Requests: 100,
+			Window:   time.Minute,
+			Auth:     RateLimitPolicy{Requests: 10, Window: time.Minute},
+			Sms:      RateLimitPolicy{Requests: 3, Window: 10 * time.Minute},
+		},
+	}
+}
+
+// defaultFile is read when no file is given with -config or APP_CONFIG.
+const defaultFile = "config.yaml"
+
+// Flags holds the command line overrides registered by RegisterFlags.
+type Flags struct {
+	fs   *flag.FlagSet
+	file *string
+	keys map[string]*string
+}
+
+// RegisterFlags adds -config and one flag per setting to fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{
+		fs:   fs,
+		file: fs.String("config", "", "path to a YAML config file (default $APP_CONFIG or "+defaultFile+")"),
+		keys: map[string]*string{},
+	}
+
+	cfg := Defaults()
+	for _, s := range settings(&cfg) {
+		f.keys[s.key] = fs.String(s.key, "", "overrides "+s.describe())
+	}
+
+	return f
+}
+
+// SetupEnv loads the configuration without command line overrides.
 func SetupEnv() (cfg AppConfig, err error) {
-	if os.Getenv("APP_ENV") == "dev" {
-		godotenv.Load()
+	return Load(nil)
+}
+
+// Load builds the configuration from all sources and validates it. All
+// validation problems are reported together in the returned error.
+func Load(flags *Flags) (AppConfig, error) {
+	// a local .env only fills in variables that are not already set
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return AppConfig{}, err
 	}
 
-	httpPort := os.Getenv("HTTP_PORT")
+	cfg := Defaults()
 
-	if len(httpPort) < 1 {
-		return AppConfig{}, errors.New("env variables not found")
+	file := os.Getenv("APP_CONFIG")
+	if flags != nil && *flags.file != "" {
+		file = *flags.file
 	}
 
-	Dsn := os.Getenv("DSN")
-	appSecret := os.Getenv("APP_SECRET")
-	TwilioAccountSid := os.Getenv("TWILIO_ACCOUNT_SID")
-	TwilioAuthToken := os.Getenv("TWILIO_AUTH_TOKEN")
-	TwilioPhoneNumber := os.Getenv("TWILIO_PHONE_NUMBER")
+	if err := loadFile(&cfg, file); err != nil {
+		return AppConfig{}, err
+	}
 
-	if len(Dsn) < 1 {
-		return AppConfig{}, errors.New("dsn variables not found")
+	if err := loadEnv(&cfg); err != nil {
+		return AppConfig{}, err
 	}
-	if len(appSecret) < 1 {
-		return AppConfig{}, errors.New("appSecret variable not found")
+
+	if flags != nil {
+		if err := loadFlags(&cfg, flags); err != nil {
+			return AppConfig{}, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return AppConfig{}, err
 	}
 
-	return AppConfig{ServerPort: httpPort, Dsn: Dsn, AppSecret: appSecret, TwilioAccountSid: TwilioAccountSid, TwilioAuthToken: TwilioAuthToken, TwilioPhoneNumber: TwilioPhoneNumber}, nil
+	return cfg, nil
 }
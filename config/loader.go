@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setting is a single leaf value of AppConfig.
+type setting struct {
+	key    string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+func (s setting) describe() string {
+	if s.env != "" {
+		return fmt.Sprintf("%s (env %s)", s.key, s.env)
+	}
+	return s.key
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// settings flattens cfg into its leaf values, addressable for writing.
+func settings(cfg *AppConfig) []setting {
+	var res []setting
+	walk(reflect.ValueOf(cfg).Elem(), "", &res)
+	return res
+}
+
+func walk(v reflect.Value, prefix string, res *[]setting) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("yaml")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), key, res)
+			continue
+		}
+
+		*res = append(*res, setting{
+			key:    key,
+			env:    f.Tag.Get("env"),
+			secret: f.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+
+	return nil
+}
+
+func loadFile(cfg *AppConfig, file string) error {
+	explicit := file != ""
+	if !explicit {
+		file = defaultFile
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parsing config file %s: %w", file, err)
+	}
+
+	return nil
+}
+
+func loadEnv(cfg *AppConfig) error {
+	var errs []error
+
+	for _, s := range settings(cfg) {
+		if s.env == "" {
+			continue
+		}
+
+		raw, ok := os.LookupEnv(s.env)
+		if !ok || raw == "" {
+			continue
+		}
+
+		if err := setValue(s.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("env %s: %w", s.env, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func loadFlags(cfg *AppConfig, flags *Flags) error {
+	set := map[string]bool{}
+	flags.fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var errs []error
+
+	for _, s := range settings(cfg) {
+		if !set[s.key] {
+			continue
+		}
+
+		if err := setValue(s.value, *flags.keys[s.key]); err != nil {
+			errs = append(errs, fmt.Errorf("flag -%s: %w", s.key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of cfg with every non-empty secret masked.
+func (c AppConfig) Redacted() AppConfig {
+	cp := c
+	for _, s := range settings(&cp) {
+		if s.secret && s.value.Kind() == reflect.String && s.value.String() != "" {
+			s.value.SetString("******")
+		}
+	}
+
+	return cp
+}
+
+// YAML renders the configuration in the same format the file is read from.
+func (c AppConfig) YAML() (string, error) {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
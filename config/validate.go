@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go-ecommerce-app/pkg/money"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	environments = []string{"dev", "test", "staging", "prod"}
+	logLevels    = []string{"debug", "info", "warn", "error"}
+	logFormats   = []string{"json", "text"}
+	exporters    = []string{"none", "stdout", "otlp"}
+
+	paymentProviders = []string{"", "stripe", "test"}
+	mediaStorages    = []string{"local", "s3"}
+	taxProviders     = []string{"none", "local"}
+
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	prefixPattern  = regexp.MustCompile(`^\+[0-9]{1,15}$`)
+)
+
+// Validate checks every section and returns all problems at once.
+func (c AppConfig) Validate() error {
+	var errs []string
+	fail := func(key string, format string, args ...any) {
+		errs = append(errs, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	if !contains(environments, c.Env) {
+		fail("env", "must be one of %s", strings.Join(environments, ", "))
+	}
+
+	if !contains(logLevels, c.Log.Level) {
+		fail("log.level", "must be one of %s", strings.Join(logLevels, ", "))
+	}
+	if !contains(logFormats, c.Log.Format) {
+		fail("log.format", "must be one of %s", strings.Join(logFormats, ", "))
+	}
+
+	if !contains(exporters, c.Tracing.Exporter) {
+		fail("tracing.exporter", "must be one of %s", strings.Join(exporters, ", "))
+	}
+	if c.Tracing.Exporter != "none" && c.Tracing.ServiceName == "" {
+		fail("tracing.service_name", "is required when tracing is enabled")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	addr := c.Server.Addr()
+	port := addr[strings.LastIndex(addr, ":")+1:]
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		fail("server.port", "must be a port number between 1 and 65535")
+	}
+
+	if c.Server.ReadTimeout <= 0 {
+		fail("server.read_timeout", "must be positive")
+	}
+	if c.Server.WriteTimeout <= 0 {
+		fail("server.write_timeout", "must be positive")
+	}
+	if c.Server.IdleTimeout <= 0 {
+		fail("server.idle_timeout", "must be positive")
+	}
+	if c.Server.BodyLimit <= 0 {
+		fail("server.body_limit", "must be positive")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		fail("server.shutdown_timeout", "must be positive")
+	}
+	if c.Server.HealthTimeout <= 0 {
+		fail("server.health_timeout", "must be positive")
+	}
+
+	if c.Database.Dsn == "" {
+		fail("database.dsn", "is required")
+	}
+	if c.Database.MaxOpenConns < 0 {
+		fail("database.max_open_conns", "must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		fail("database.max_idle_conns", "must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("database.max_idle_conns", "must not exceed database.max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		fail("database.conn_max_lifetime", "must not be negative")
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		fail("database.conn_max_idle_time", "must not be negative")
+	}
+
+	if c.Auth.Secret == "" {
+		fail("auth.secret", "is required")
+	}
+	if c.Auth.TokenTTL <= 0 {
+		fail("auth.token_ttl", "must be positive")
+	}
+
+	if tw := c.Notifications.Twilio; tw.Configured() {
+		if tw.AccountSid == "" {
+			fail("notifications.twilio.account_sid", "is required when twilio is configured")
+		}
+		if tw.AuthToken == "" {
+			fail("notifications.twilio.auth_token", "is required when twilio is configured")
+		}
+		if tw.PhoneNumber == "" {
+			fail("notifications.twilio.phone_number", "is required when twilio is configured")
+		}
+	}
+
+	sms := c.Notifications.Sms
+	if !countryPattern.MatchString(sms.DefaultCountry) {
+		fail("notifications.sms.default_country", "must be an ISO 3166-1 alpha-2 code")
+	}
+	if len(sms.AllowedCountries) == 0 {
+		fail("notifications.sms.allowed_countries", "must list at least one country")
+	}
+	for _, country := range sms.AllowedCountries {
+		if !countryPattern.MatchString(country) {
+			fail("notifications.sms.allowed_countries", "%q is not an ISO 3166-1 alpha-2 code", country)
+		}
+	}
+	for _, prefix := range sms.BlockedPrefixes {
+		if !prefixPattern.MatchString(prefix) {
+			fail("notifications.sms.blocked_prefixes", "%q must be + followed by digits", prefix)
+		}
+	}
+	if sms.MaxPerNumberPerDay <= 0 {
+		fail("notifications.sms.max_per_number_per_day", "must be positive")
+	}
+	if sms.MaxPerHour <= 0 {
+		fail("notifications.sms.max_per_hour", "must be positive")
+	}
+
+	if !contains(paymentProviders, c.Payments.Provider) {
+		fail("payments.provider", "must be empty or one of stripe, test")
+	}
+	if c.Payments.Provider == "stripe" && c.Payments.SecretKey == "" {
+		fail("payments.secret_key", "is required when payments.provider is stripe")
+	}
+	if c.Payments.Provider != "" && c.Payments.WebhookSecret == "" {
+		fail("payments.webhook_secret", "is required when payments.provider is set")
+	}
+	if !money.Known(c.Payments.Currency) {
+		fail("payments.currency", "must be a supported ISO 4217 code")
+	}
+
+	if c.Checkout.ReservationTTL <= 0 {
+		fail("checkout.reservation_ttl", "must be positive")
+	}
+	if c.Checkout.SweepInterval <= 0 {
+		fail("checkout.sweep_interval", "must be positive")
+	}
+
+	if !contains(taxProviders, c.Tax.Provider) {
+		fail("tax.provider", "must be one of %s", strings.Join(taxProviders, ", "))
+	}
+	if c.Shipping.VolumetricDivisor < 0 {
+		fail("shipping.volumetric_divisor", "must not be negative")
+	}
+
+	media := c.Media
+	if !contains(mediaStorages, media.Storage) {
+		fail("media.storage", "must be one of %s", strings.Join(mediaStorages, ", "))
+	}
+	if media.MaxFileSize <= 0 {
+		fail("media.max_file_size", "must be positive")
+	}
+	if media.MaxFileSize > c.Server.BodyLimit {
+		fail("media.max_file_size", "must not exceed server.body_limit")
+	}
+	if media.MaxFiles <= 0 {
+		fail("media.max_files", "must be positive")
+	}
+	if media.MaxProductImages <= 0 {
+		fail("media.max_product_images", "must be positive")
+	}
+	if media.MinDimension <= 0 {
+		fail("media.min_dimension", "must be positive")
+	}
+	if media.MaxDimension < media.MinDimension {
+		fail("media.max_dimension", "must not be less than media.min_dimension")
+	}
+	if media.ThumbnailSize <= 0 {
+		fail("media.thumbnail_size", "must be positive")
+	}
+	if media.OrphanAge <= 0 {
+		fail("media.orphan_age", "must be positive")
+	}
+	if media.CleanupInterval <= 0 {
+		fail("media.cleanup_interval", "must be positive")
+	}
+	switch media.Storage {
+	case "local":
+		if media.Local.Dir == "" {
+			fail("media.local.dir", "is required when media.storage is local")
+		}
+		if !strings.HasPrefix(media.Local.BaseUrl, "/") && !strings.HasPrefix(media.Local.BaseUrl, "http") {
+			fail("media.local.base_url", "must be a path starting with / or an absolute url")
+		}
+	case "s3":
+		if media.S3.Bucket == "" {
+			fail("media.s3.bucket", "is required when media.storage is s3")
+		}
+		if media.S3.Region == "" {
+			fail("media.s3.region", "is required when media.storage is s3")
+		}
+		if media.S3.AccessKey == "" || media.S3.SecretKey == "" {
+			fail("media.s3.access_key", "access_key and secret_key are required when media.storage is s3")
+		}
+	}
+
+	if c.RateLimits.Enabled {
+		if c.RateLimits.Requests <= 0 {
+			fail("rate_limits.requests", "must be positive")
+		}
+		if c.RateLimits.Window <= 0 {
+			fail("rate_limits.window", "must be positive")
+		}
+		policies := []struct {
+			key    string
+			policy RateLimitPolicy
+		}{
+			{"rate_limits.auth", c.RateLimits.Auth},
+			{"rate_limits.sms", c.RateLimits.Sms},
+		}
+		for _, p := range policies {
+			if p.policy.Requests <= 0 {
+				fail(p.key+".requests", "must be positive")
+			}
+			if p.policy.Window <= 0 {
+				fail(p.key+".window", "must be positive")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid configuration:\n  " + strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/database/migrations"
+	"go-ecommerce-app/internal/health"
+	"strings"
+	"sync/atomic"
+)
+
+// livenessChecks only look at the process itself; a failing liveness probe
+// gets the process restarted, so external dependencies don't belong here.
+func livenessChecks(cfg config.AppConfig, workers *workerGroup) []health.Check {
+	return []health.Check{
+		{
+			Name:    "workers",
+			Timeout: cfg.Server.HealthTimeout,
+			Run: func(ctx context.Context) error {
+				if stopped := workers.Stopped(); len(stopped) > 0 {
+					return errors.New("stopped: " + strings.Join(stopped, ", "))
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// readinessChecks decide whether the instance should receive traffic.
+func readinessChecks(cfg config.AppConfig, sqlDB *sql.DB, migrator *migrations.Migrator, draining *atomic.Bool) []health.Check {
+	timeout := cfg.Server.HealthTimeout
+
+	return []health.Check{
+		{
+			Name:    "lifecycle",
+			Timeout: timeout,
+			Run: func(ctx context.Context) error {
+				if draining.Load() {
+					return errors.New("shutting down")
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "database",
+			Timeout: timeout,
+			Run:     sqlDB.PingContext,
+		},
+		{
+			Name:    "migrations",
+			Timeout: timeout,
+			Run: func(ctx context.Context) error {
+				return migrator.WithContext(ctx).CheckCurrent()
+			},
+		},
+		{
+			Name:    "notifications",
+			Timeout: timeout,
+			Run: func(ctx context.Context) error {
+				if !cfg.Notifications.Twilio.Configured() {
+					return health.Disabled("twilio is not configured")
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "payments",
+			Timeout: timeout,
+			Run: func(ctx context.Context) error {
+				if cfg.Payments.Provider == "" {
+					return health.Disabled("no payment provider configured")
+				}
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-ecommerce-app/internal/logging"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+type shutdownStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// shutdown runs the steps in order, all sharing the deadline of ctx. A step
+// that fails or times out is reported but does not stop the ones after it,
+// so the database pool is closed even if draining took too long.
+func shutdown(ctx context.Context, steps []shutdownStep) error {
+	var errs []error
+
+	for _, s := range steps {
+		if err := s.fn(ctx); err != nil {
+			slog.Error("shutdown step failed", "step", s.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		slog.Info("shutdown step done", "step", s.name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// workerGroup runs long-lived background loops that stop when their context
+// is cancelled.
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func newWorkerGroup() *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{ctx: ctx, cancel: cancel, running: map[string]bool{}}
+}
+
+func (g *workerGroup) Go(name string, run func(ctx context.Context)) {
+	g.setRunning(name, true)
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+		defer g.setRunning(name, false)
+		ctx := logging.With(g.ctx, slog.String("worker", name))
+		defer func() {
+			// a panicking worker stops and fails the liveness check rather
+			// than take the server down with it
+			if r := recover(); r != nil {
+				slog.ErrorContext(ctx, "worker panicked", "panic", r, "stack", string(debug.Stack()))
+			}
+		}()
+		run(ctx)
+		slog.InfoContext(ctx, "worker stopped")
+	}()
+}
+
+func (g *workerGroup) setRunning(name string, running bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running[name] = running
+}
+
+// Stopped lists workers whose loop has returned.
+func (g *workerGroup) Stopped() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var stopped []string
+	for name, running := range g.running {
+		if !running {
+			stopped = append(stopped, name)
+		}
+	}
+
+	return stopped
+}
+
+// Stop cancels the workers and waits for their current iteration to finish,
+// or until ctx is done.
+func (g *workerGroup) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
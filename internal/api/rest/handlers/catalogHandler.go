@@ -3,13 +3,16 @@ package handlers
 import (
 	"github.com/gofiber/fiber/v2"
 	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
 	"go-ecommerce-app/internal/repository"
 	"go-ecommerce-app/internal/service"
-	"log"
+	"log/slog"
+	"net/http"
 )
 
 type CatalogHandler struct {
-	svc service.CatalogService
+	svc      service.CatalogService
+	currency service.CurrencyService
 }
 
 func SetupCatalogRoutes(rh *rest.RestHandler) {
@@ -18,19 +21,21 @@ func SetupCatalogRoutes(rh *rest.RestHandler) {
 	// Create an instance of user service and inject to handler
 	svc := service.CatalogService{
 		Repo:   repository.NewCatalogRepository(rh.DB),
+		Tx:     repository.NewUnitOfWork(rh.DB),
 		Auth:   rh.Auth,
 		Config: rh.Config,
 	}
 
 	handler := CatalogHandler{
-		svc: svc,
+		svc:      svc,
+		currency: NewCurrencyService(rh),
 	}
 
 	// Public Endpoints
-	app.Get("/products")
-	app.Get("/products/:id")
-	app.Get("/categories")
-	app.Get("/categories/:id")
+	app.Get("/products", handler.GetPublicProducts)
+	app.Get("/products/:id", handler.GetPublicProduct)
+	app.Get("/categories", handler.GetCategories)
+	app.Get("/categories/:id", handler.GetCategory)
 
 	// Private Endpoints
 	selRoutes := app.Group("/seller", rh.Auth.AuthorizeSeller)
@@ -45,6 +50,77 @@ func SetupCatalogRoutes(rh *rest.RestHandler) {
 	selRoutes.Patch("/products/:id", handler.EditProduct)
 	selRoutes.Put("/products/:id", handler.UpdateProduct)
 	selRoutes.Delete("/products/:id", handler.DeleteProduct)
+	selRoutes.Put("/products/:id/dimensions", handler.SetDimensions)
+	// Variants
+	selRoutes.Put("/products/:id/options", handler.SetOptions)
+	selRoutes.Get("/products/:id/variants", handler.GetVariants)
+	selRoutes.Post("/products/:id/variants", handler.CreateVariant)
+	selRoutes.Post("/products/:id/variants/generate", handler.GenerateVariants)
+	selRoutes.Patch("/products/:id/variants/:variantId", handler.UpdateVariant)
+	selRoutes.Delete("/products/:id/variants/:variantId", handler.DeleteVariant)
+}
+
+// Public
+
+func (h CatalogHandler) GetPublicProducts(ctx *fiber.Ctx) error {
+	rates, currency, err := displayCurrency(ctx, h.currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	products, err := h.svc.GetProducts(ctx.UserContext())
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "products", dto.ProductResponsesIn(dto.NewProductResponsesFrom(products), rates, currency))
+}
+
+func (h CatalogHandler) GetPublicProduct(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rates, currency, err := displayCurrency(ctx, h.currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.GetProduct(ctx.UserContext(), id)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "product", dto.NewProductResponse(*product).In(rates, currency))
+}
+
+func (h CatalogHandler) GetCategories(ctx *fiber.Ctx) error {
+	categories, err := h.svc.GetCategories(ctx.UserContext())
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "categories", dto.NewCategoryResponses(categories))
+}
+
+func (h CatalogHandler) GetCategory(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rates, currency, err := displayCurrency(ctx, h.currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	category, err := h.svc.GetCategory(ctx.UserContext(), id)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "category", dto.NewCategoryResponse(*category).In(rates, currency))
 }
 
 // Categories
@@ -52,7 +128,7 @@ func SetupCatalogRoutes(rh *rest.RestHandler) {
 func (h CatalogHandler) CreateCategories(ctx *fiber.Ctx) error {
 	user := h.svc.Auth.GetCurrentUser(ctx)
 
-	log.Printf("current user: %v", user.ID)
+	slog.DebugContext(ctx.UserContext(), "create category", "seller_id", user.ID)
 
 	return rest.SuccessResponse(ctx, "category endpoint", nil)
 }
@@ -72,11 +148,30 @@ func (h CatalogHandler) CreateProducts(ctx *fiber.Ctx) error {
 }
 
 func (h CatalogHandler) GetProducts(ctx *fiber.Ctx) error {
-	return rest.SuccessResponse(ctx, "Get productS endpoint", nil)
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	products, err := h.svc.GetSellerProducts(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "products", dto.NewProductResponsesFrom(products))
 }
 
 func (h CatalogHandler) GetProduct(ctx *fiber.Ctx) error {
-	return rest.SuccessResponse(ctx, "Get product endpoint", nil)
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.GetSellerProduct(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "product", dto.NewProductResponse(*product))
 }
 
 func (h CatalogHandler) EditProduct(ctx *fiber.Ctx) error {
@@ -90,3 +185,154 @@ func (h CatalogHandler) UpdateProduct(ctx *fiber.Ctx) error {
 func (h CatalogHandler) DeleteProduct(ctx *fiber.Ctx) error {
 	return rest.SuccessResponse(ctx, "Delete product endpoint", nil)
 }
+
+func (h CatalogHandler) SetDimensions(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ProductDimensionsInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.SetDimensions(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "product dimensions updated", dto.NewProductResponse(*product))
+}
+
+// Variants
+
+func (h CatalogHandler) SetOptions(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ProductOptionsInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.SetOptions(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "product options updated", dto.NewProductResponse(*product))
+}
+
+func (h CatalogHandler) GetVariants(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.GetSellerProduct(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "variants", dto.NewVariantResponses(*product, product.Variants))
+}
+
+func (h CatalogHandler) CreateVariant(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.VariantInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.CreateVariant(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "variant created",
+		"data":    dto.NewVariantResponses(*product, product.Variants),
+	})
+}
+
+func (h CatalogHandler) GenerateVariants(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.VariantMatrixInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.GenerateVariants(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "variants generated", dto.NewVariantResponses(*product, product.Variants))
+}
+
+func (h CatalogHandler) UpdateVariant(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	variantId, err := rest.ParamId(ctx, "variantId")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.VariantUpdateInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.UpdateVariant(ctx.UserContext(), id, variantId, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "variant updated", dto.NewVariantResponses(*product, product.Variants))
+}
+
+func (h CatalogHandler) DeleteVariant(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	variantId, err := rest.ParamId(ctx, "variantId")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteVariant(ctx.UserContext(), id, variantId, user.ID); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "variant deleted", nil)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CouponHandler struct {
+	svc service.CouponService
+}
+
+func SetupCouponRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := CouponHandler{
+		svc: service.CouponService{
+			Repo:   repository.NewCouponRepository(rh.DB),
+			Auth:   rh.Auth,
+			Config: rh.Config,
+		},
+	}
+
+	// Private Endpoints
+	adminRoutes := app.Group("/admin/coupons", rh.Auth.AuthorizeAdmin)
+	adminRoutes.Get("/", handler.GetCoupons)
+	adminRoutes.Post("/", handler.CreateCoupon)
+	adminRoutes.Put("/:id", handler.UpdateCoupon)
+	adminRoutes.Delete("/:id", handler.DeleteCoupon)
+}
+
+func (h CouponHandler) GetCoupons(ctx *fiber.Ctx) error {
+	coupons, err := h.svc.GetCoupons(ctx.UserContext())
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "coupons", dto.NewCouponResponses(coupons))
+}
+
+func (h CouponHandler) CreateCoupon(ctx *fiber.Ctx) error {
+	req := dto.CouponInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	coupon, err := h.svc.CreateCoupon(ctx.UserContext(), req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "coupon created",
+		"data":    dto.NewCouponResponse(*coupon),
+	})
+}
+
+func (h CouponHandler) UpdateCoupon(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.CouponInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	coupon, err := h.svc.UpdateCoupon(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "coupon updated", dto.NewCouponResponse(*coupon))
+}
+
+func (h CouponHandler) DeleteCoupon(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteCoupon(ctx.UserContext(), id); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "coupon deleted", nil)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"go-ecommerce-app/pkg/money"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CurrencyHandler struct {
+	svc service.CurrencyService
+}
+
+func SetupCurrencyRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := CurrencyHandler{
+		svc: NewCurrencyService(rh),
+	}
+
+	// Private Endpoints
+	adminRoutes := app.Group("/admin/currency-rates", rh.Auth.AuthorizeAdmin)
+	adminRoutes.Get("/", handler.GetRates)
+	adminRoutes.Put("/", handler.SetRates)
+	adminRoutes.Delete("/:currency", handler.DeleteRate)
+}
+
+// NewCurrencyService builds the currency service shared by the admin routes
+// and the handlers showing prices.
+func NewCurrencyService(rh *rest.RestHandler) service.CurrencyService {
+	return service.CurrencyService{
+		Repo:   repository.NewCurrencyRepository(rh.DB),
+		Auth:   rh.Auth,
+		Config: rh.Config,
+	}
+}
+
+// displayCurrency resolves the currency the client asked prices in.
+func displayCurrency(ctx *fiber.Ctx, svc service.CurrencyService) (money.Rates, string, error) {
+	codes, explicit := rest.Currencies(ctx)
+	return svc.DisplayCurrency(ctx.UserContext(), codes, explicit)
+}
+
+func (h CurrencyHandler) GetRates(ctx *fiber.Ctx) error {
+	rates, err := h.svc.GetRates(ctx.UserContext())
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "currency rates", &fiber.Map{
+		"base":  h.svc.Config.Payments.Currency,
+		"rates": rates,
+	})
+}
+
+func (h CurrencyHandler) SetRates(ctx *fiber.Ctx) error {
+	req := dto.CurrencyRatesInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rates, err := h.svc.SetRates(ctx.UserContext(), req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "currency rates updated", &fiber.Map{
+		"base":  h.svc.Config.Payments.Currency,
+		"rates": rates,
+	})
+}
+
+func (h CurrencyHandler) DeleteRate(ctx *fiber.Ctx) error {
+	if err := h.svc.DeleteRate(ctx.UserContext(), ctx.Params("currency")); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "currency rate deleted", nil)
+}
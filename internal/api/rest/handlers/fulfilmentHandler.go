@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FulfilmentHandler struct {
+	svc service.FulfilmentService
+}
+
+func SetupFulfilmentRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := FulfilmentHandler{
+		svc: service.FulfilmentService{
+			Repo:     repository.NewOrderRepository(rh.DB),
+			Tx:       repository.NewUnitOfWork(rh.DB),
+			Webhooks: NewWebhookService(rh),
+			Auth:     rh.Auth,
+			Config:   rh.Config,
+		},
+	}
+
+	// Private Endpoints
+	selRoutes := app.Group("/seller/orders", rh.Auth.AuthorizeSeller)
+	selRoutes.Get("/", handler.GetOrders)
+	selRoutes.Get("/:id", handler.GetOrder)
+	selRoutes.Get("/:id/shipments", handler.GetShipments)
+	selRoutes.Post("/:id/shipments", handler.CreateShipment)
+}
+
+// GetOrders lists the seller's part of their orders, the paid ones unless
+// another status is asked for.
+func (h FulfilmentHandler) GetOrders(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	orders, err := h.svc.GetOrders(ctx.UserContext(), user.ID, ctx.Query("status", domain.OrderPaid))
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "orders", dto.NewOrderResponses(orders))
+}
+
+func (h FulfilmentHandler) GetOrder(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	order, err := h.svc.GetOrder(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "order", dto.NewOrderResponse(*order))
+}
+
+func (h FulfilmentHandler) GetShipments(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	shipments, err := h.svc.GetShipments(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipments", dto.NewShipmentResponses(shipments))
+}
+
+func (h FulfilmentHandler) CreateShipment(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ShipmentInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	shipment, err := h.svc.CreateShipment(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "shipment created",
+		"data":    dto.NewShipmentResponse(*shipment),
+	})
+}
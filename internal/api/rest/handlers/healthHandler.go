@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/buildinfo"
+	"go-ecommerce-app/internal/health"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthHandler struct {
+	liveness  []health.Check
+	readiness []health.Check
+}
+
+// SetupHealthRoutes registers the unauthenticated probe endpoints. It must
+// run before any route group that applies auth middleware to "/".
+func SetupHealthRoutes(rh *rest.RestHandler, liveness []health.Check, readiness []health.Check) {
+	app := rh.App
+
+	handler := HealthHandler{
+		liveness:  liveness,
+		readiness: readiness,
+	}
+
+	app.Get("/healthz", handler.Liveness)
+	app.Get("/readyz", handler.Readiness)
+	app.Get("/version", handler.Version)
+}
+
+func reportStatus(report health.Report) int {
+	if report.Healthy() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+func (h HealthHandler) Liveness(ctx *fiber.Ctx) error {
+	report := health.Run(ctx.UserContext(), h.liveness)
+	return ctx.Status(reportStatus(report)).JSON(report)
+}
+
+func (h HealthHandler) Readiness(ctx *fiber.Ctx) error {
+	report := health.Run(ctx.UserContext(), h.readiness)
+	return ctx.Status(reportStatus(report)).JSON(report)
+}
+
+func (h HealthHandler) Version(ctx *fiber.Ctx) error {
+	return ctx.Status(http.StatusOK).JSON(buildinfo.Get())
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MediaHandler struct {
+	svc service.MediaService
+}
+
+// SetupMediaRoutes registers the product image routes and, with local
+// storage, the route serving the files. It must run before SetupUserRoutes,
+// whose authorized group on "/" would otherwise cover the files.
+func SetupMediaRoutes(rh *rest.RestHandler) {
+	app := rh.App
+	cfg := rh.Config.Media
+
+	handler := MediaHandler{
+		svc: NewMediaService(rh),
+	}
+
+	// Public Endpoints
+	if cfg.Storage == "local" && strings.HasPrefix(cfg.Local.BaseUrl, "/") {
+		// file names are random and never reused, so they can be cached forever
+		app.Static(cfg.Local.BaseUrl, cfg.Local.Dir, fiber.Static{MaxAge: 365 * 24 * 60 * 60})
+	}
+
+	// Private Endpoints
+	selRoutes := app.Group("/seller/products/:id/images", rh.Auth.AuthorizeSeller)
+	selRoutes.Get("/", handler.GetImages)
+	selRoutes.Post("/", handler.UploadImages)
+	selRoutes.Put("/order", handler.ReorderImages)
+	selRoutes.Delete("/:imageId", handler.DeleteImage)
+}
+
+// NewMediaService builds the media service shared by the seller routes and
+// the background orphan cleanup.
+func NewMediaService(rh *rest.RestHandler) service.MediaService {
+	return service.MediaService{
+		Repo: repository.NewMediaRepository(rh.DB),
+		Catalog: service.CatalogService{
+			Repo:   repository.NewCatalogRepository(rh.DB),
+			Tx:     repository.NewUnitOfWork(rh.DB),
+			Auth:   rh.Auth,
+			Config: rh.Config,
+		},
+		Tx:      repository.NewUnitOfWork(rh.DB),
+		Storage: rh.Storage,
+		Auth:    rh.Auth,
+		Config:  rh.Config,
+	}
+}
+
+func (h MediaHandler) GetImages(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.Catalog.GetSellerProduct(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "images", dto.NewProductImageResponses(product.Images))
+}
+
+// UploadImages takes a multipart form with one or more files in the
+// "images" field.
+func (h MediaHandler) UploadImages(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	uploads, err := h.readUploads(ctx, "images")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.UploadImages(ctx.UserContext(), id, user.ID, uploads)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "images uploaded",
+		"data":    dto.NewProductImageResponses(product.Images),
+	})
+}
+
+func (h MediaHandler) ReorderImages(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ImageOrderInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.ReorderImages(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "images reordered", dto.NewProductImageResponses(product.Images))
+}
+
+func (h MediaHandler) DeleteImage(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	imageId, err := rest.ParamId(ctx, "imageId")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	product, err := h.svc.DeleteImage(ctx.UserContext(), id, imageId, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "image deleted", dto.NewProductImageResponses(product.Images))
+}
+
+// readUploads reads the files of a multipart field. Files are read up to
+// one byte past the size limit, enough for the service to reject them.
+func (h MediaHandler) readUploads(ctx *fiber.Ctx, field string) ([]dto.ImageUpload, error) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, rest.ErrInvalidBody
+	}
+
+	limit := int64(h.svc.Config.Media.MaxFileSize) + 1
+
+	uploads := make([]dto.ImageUpload, 0, len(form.File[field]))
+	for _, fh := range form.File[field] {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(io.LimitReader(f, limit))
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		uploads = append(uploads, dto.ImageUpload{Filename: fh.Filename, Data: data})
+	}
+
+	return uploads, nil
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"go-ecommerce-app/pkg/money"
+	"go-ecommerce-app/pkg/tax"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderHandler struct {
+	svc      service.OrderService
+	cart     service.CartService
+	shipping service.ShippingService
+}
+
+// SetupOrderRoutes registers the cart, checkout and payment webhook routes.
+// It must run before SetupUserRoutes, whose authorized group on "/" would
+// otherwise cover the payment webhook.
+func SetupOrderRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := OrderHandler{
+		svc: NewOrderService(rh),
+		cart: service.CartService{
+			Repo:     repository.NewCartRepository(rh.DB),
+			Catalog:  repository.NewCatalogRepository(rh.DB),
+			Coupons:  repository.NewCouponRepository(rh.DB),
+			Currency: NewCurrencyService(rh),
+			Auth:     rh.Auth,
+			Config:   rh.Config,
+		},
+		shipping: NewShippingService(rh),
+	}
+
+	// Public Endpoints, authenticated by the provider's signature
+	app.Post("/payments/webhook", handler.PaymentWebhook)
+
+	// Private Endpoints
+	cartRoutes := app.Group("/cart", rh.Auth.Authorize)
+	cartRoutes.Get("/", handler.GetCart)
+	cartRoutes.Post("/", handler.SetCartItem)
+	cartRoutes.Get("/shipping-options", handler.GetShippingOptions)
+	cartRoutes.Post("/coupon", handler.ApplyCoupon)
+	cartRoutes.Delete("/coupon", handler.RemoveCoupon)
+
+	orderRoutes := app.Group("/order", rh.Auth.Authorize)
+	orderRoutes.Post("/", handler.Checkout)
+	orderRoutes.Get("/", handler.GetOrders)
+	orderRoutes.Get("/:id", handler.GetOrder)
+	orderRoutes.Post("/:id/cancel", handler.CancelOrder)
+}
+
+// NewOrderService builds the order service shared by the routes and the
+// background reservation sweeper.
+func NewOrderService(rh *rest.RestHandler) service.OrderService {
+	return service.OrderService{
+		Repo:     repository.NewOrderRepository(rh.DB),
+		Tx:       repository.NewUnitOfWork(rh.DB),
+		Payments: rh.Payments,
+		Webhooks: NewWebhookService(rh),
+		Currency: NewCurrencyService(rh),
+		Tax:      tax.New(rh.Config.Tax, NewTaxService(rh)),
+		Auth:     rh.Auth,
+		Config:   rh.Config,
+	}
+}
+
+func (h OrderHandler) GetCart(ctx *fiber.Ctx) error {
+	user := h.cart.Auth.GetCurrentUser(ctx)
+
+	rates, currency, err := displayCurrency(ctx, h.cart.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	items, err := h.cart.GetCart(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	res, err := h.cartResponse(ctx, user.ID, items, rates, currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "cart", res)
+}
+
+func (h OrderHandler) SetCartItem(ctx *fiber.Ctx) error {
+	user := h.cart.Auth.GetCurrentUser(ctx)
+
+	req := dto.CartItemInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rates, currency, err := displayCurrency(ctx, h.cart.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	items, err := h.cart.SetItem(ctx.UserContext(), user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	res, err := h.cartResponse(ctx, user.ID, items, rates, currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "cart updated", res)
+}
+
+// ApplyCoupon applies a coupon code to the cart, replacing any other.
+func (h OrderHandler) ApplyCoupon(ctx *fiber.Ctx) error {
+	user := h.cart.Auth.GetCurrentUser(ctx)
+
+	req := dto.CouponCodeInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rates, currency, err := displayCurrency(ctx, h.cart.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	items, err := h.cart.ApplyCoupon(ctx.UserContext(), user.ID, req, rates, currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	res, err := h.cartResponse(ctx, user.ID, items, rates, currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "coupon applied", res)
+}
+
+func (h OrderHandler) RemoveCoupon(ctx *fiber.Ctx) error {
+	user := h.cart.Auth.GetCurrentUser(ctx)
+
+	rates, currency, err := displayCurrency(ctx, h.cart.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	items, err := h.cart.RemoveCoupon(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "coupon removed", dto.NewCartResponse(items).In(rates, currency))
+}
+
+// cartResponse shows the cart in currency with the discount of its coupon,
+// or why the coupon no longer applies.
+func (h OrderHandler) cartResponse(ctx *fiber.Ctx, userId uint, items []*domain.CartItem, rates money.Rates, currency string) (dto.CartResponse, error) {
+	res := dto.NewCartResponse(items).In(rates, currency)
+
+	coupon, err := h.cart.GetCoupon(ctx.UserContext(), userId)
+	if err != nil || coupon == nil {
+		return res, err
+	}
+
+	var problem *domain.Error
+	discount, err := h.cart.Discount(ctx.UserContext(), userId, *coupon, items, rates, currency)
+	if err != nil && !errors.As(err, &problem) {
+		return res, err
+	}
+
+	return res.WithCoupon(*coupon, discount, problem), nil
+}
+
+// GetShippingOptions quotes the shipping methods for the cart and the
+// user's default shipping address.
+func (h OrderHandler) GetShippingOptions(ctx *fiber.Ctx) error {
+	user := h.cart.Auth.GetCurrentUser(ctx)
+
+	rates, currency, err := displayCurrency(ctx, h.cart.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	quotes, err := h.shipping.GetOptions(ctx.UserContext(), user.ID, rates, currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping options", dto.NewShippingOptionResponses(quotes))
+}
+
+func (h OrderHandler) Checkout(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	req := dto.CheckoutInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	// the order is charged in the currency the cart was shown in
+	_, currency, err := displayCurrency(ctx, h.svc.Currency)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	order, payment, err := h.svc.Checkout(ctx.UserContext(), user.ID, currency, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "order created",
+		"data": dto.CheckoutResponse{
+			Order:   dto.NewOrderResponse(*order),
+			Payment: payment,
+		},
+	})
+}
+
+func (h OrderHandler) GetOrders(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	orders, err := h.svc.GetOrders(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "orders", dto.NewOrderResponses(orders))
+}
+
+func (h OrderHandler) GetOrder(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	order, err := h.svc.GetOrder(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "order", dto.NewOrderResponse(*order))
+}
+
+func (h OrderHandler) CancelOrder(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	order, err := h.svc.Cancel(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "order cancelled", dto.NewOrderResponse(*order))
+}
+
+// PaymentWebhook receives events from the payment provider. The signature
+// covers the raw body, so it is passed on untouched.
+func (h OrderHandler) PaymentWebhook(ctx *fiber.Ctx) error {
+	header := http.Header{}
+	for k, v := range ctx.GetReqHeaders() {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	if err := h.svc.HandlePaymentEvent(ctx.UserContext(), ctx.Body(), header); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "event received", nil)
+}
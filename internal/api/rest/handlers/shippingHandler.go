@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShippingHandler struct {
+	svc service.ShippingService
+}
+
+func SetupShippingRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := ShippingHandler{
+		svc: NewShippingService(rh),
+	}
+
+	// Private Endpoints
+	adminRoutes := app.Group("/admin/shipping", rh.Auth.AuthorizeAdmin)
+	adminRoutes.Get("/zones", handler.GetZones)
+	adminRoutes.Post("/zones", handler.CreateZone)
+	adminRoutes.Put("/zones/:id", handler.UpdateZone)
+	adminRoutes.Delete("/zones/:id", handler.DeleteZone)
+	adminRoutes.Post("/zones/:id/methods", handler.CreateMethod)
+	adminRoutes.Put("/methods/:id", handler.UpdateMethod)
+	adminRoutes.Delete("/methods/:id", handler.DeleteMethod)
+}
+
+// NewShippingService builds the shipping service shared by the admin routes
+// and the cart's shipping options.
+func NewShippingService(rh *rest.RestHandler) service.ShippingService {
+	return service.ShippingService{
+		Repo:   repository.NewShippingRepository(rh.DB),
+		Cart:   repository.NewCartRepository(rh.DB),
+		Users:  repository.NewUserRepository(rh.DB),
+		Auth:   rh.Auth,
+		Config: rh.Config,
+	}
+}
+
+func (h ShippingHandler) GetZones(ctx *fiber.Ctx) error {
+	zones, err := h.svc.GetZones(ctx.UserContext())
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping zones", zones)
+}
+
+func (h ShippingHandler) CreateZone(ctx *fiber.Ctx) error {
+	req := dto.ShippingZoneInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	zone, err := h.svc.CreateZone(ctx.UserContext(), req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "shipping zone created",
+		"data":    zone,
+	})
+}
+
+func (h ShippingHandler) UpdateZone(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ShippingZoneInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	zone, err := h.svc.UpdateZone(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping zone updated", zone)
+}
+
+func (h ShippingHandler) DeleteZone(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteZone(ctx.UserContext(), id); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping zone deleted", nil)
+}
+
+func (h ShippingHandler) CreateMethod(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ShippingMethodInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	method, err := h.svc.CreateMethod(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "shipping method created",
+		"data":    method,
+	})
+}
+
+func (h ShippingHandler) UpdateMethod(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.ShippingMethodInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	method, err := h.svc.UpdateMethod(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping method updated", method)
+}
+
+func (h ShippingHandler) DeleteMethod(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteMethod(ctx.UserContext(), id); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "shipping method deleted", nil)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxHandler struct {
+	svc service.TaxService
+}
+
+func SetupTaxRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := TaxHandler{
+		svc: NewTaxService(rh),
+	}
+
+	// Private Endpoints
+	adminRoutes := app.Group("/admin/tax", rh.Auth.AuthorizeAdmin)
+	adminRoutes.Get("/rates", handler.GetRates)
+	adminRoutes.Post("/rates", handler.CreateRate)
+	adminRoutes.Put("/rates/:id", handler.UpdateRate)
+	adminRoutes.Delete("/rates/:id", handler.DeleteRate)
+	adminRoutes.Put("/categories/:id", handler.SetCategoryClass)
+}
+
+// NewTaxService builds the tax service shared by the admin routes and the
+// local tax calculator used at checkout.
+func NewTaxService(rh *rest.RestHandler) service.TaxService {
+	return service.TaxService{
+		Repo:    repository.NewTaxRepository(rh.DB),
+		Catalog: repository.NewCatalogRepository(rh.DB),
+		Auth:    rh.Auth,
+		Config:  rh.Config,
+	}
+}
+
+// GetRates lists the tax table, optionally for one ?country=.
+func (h TaxHandler) GetRates(ctx *fiber.Ctx) error {
+	rates, err := h.svc.GetRates(ctx.UserContext(), ctx.Query("country"))
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "tax rates", rates)
+}
+
+func (h TaxHandler) CreateRate(ctx *fiber.Ctx) error {
+	req := dto.TaxRateInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rate, err := h.svc.CreateRate(ctx.UserContext(), req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "tax rate created",
+		"data":    rate,
+	})
+}
+
+func (h TaxHandler) UpdateRate(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.TaxRateInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	rate, err := h.svc.UpdateRate(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "tax rate updated", rate)
+}
+
+func (h TaxHandler) DeleteRate(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteRate(ctx.UserContext(), id); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "tax rate deleted", nil)
+}
+
+func (h TaxHandler) SetCategoryClass(ctx *fiber.Ctx) error {
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.TaxClassInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	category, err := h.svc.SetCategoryClass(ctx.UserContext(), id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "category tax class updated", dto.NewCategoryResponse(*category))
+}
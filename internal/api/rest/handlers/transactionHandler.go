@@ -1 +1,42 @@
 package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TransactionHandler struct {
+	svc service.LedgerService
+}
+
+func SetupTransactionRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	handler := TransactionHandler{
+		svc: service.LedgerService{
+			Repo:   repository.NewLedgerRepository(rh.DB),
+			Auth:   rh.Auth,
+			Config: rh.Config,
+		},
+	}
+
+	// Private Endpoints
+	selRoutes := app.Group("/seller/transactions", rh.Auth.AuthorizeSeller)
+	selRoutes.Get("/", handler.GetLedger)
+}
+
+// GetLedger shows what the seller earned from paid orders.
+func (h TransactionHandler) GetLedger(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	balances, entries, err := h.svc.GetLedger(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "transactions", dto.NewLedgerResponse(balances, entries))
+}
@@ -3,9 +3,10 @@ package handlers
 import (
 	"go-ecommerce-app/internal/api/rest"
 	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/ratelimit"
 	"go-ecommerce-app/internal/repository"
 	"go-ecommerce-app/internal/service"
-	"log"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -21,6 +22,8 @@ func SetupUserRoutes(rh *rest.RestHandler) {
 	// Create an instance of user service and inject to handler
 	svc := service.UserService{
 		Repo:   repository.NewUserRepository(rh.DB),
+		Tx:     repository.NewUnitOfWork(rh.DB),
+		Sms:    service.NewSmsService(repository.NewSmsRepository(rh.DB), rh.Notify, rh.Config.Notifications.Sms),
 		Auth:   rh.Auth,
 		Config: rh.Config,
 	}
@@ -29,42 +32,37 @@ func SetupUserRoutes(rh *rest.RestHandler) {
 		svc: svc,
 	}
 
+	limiter := rh.Limiter
+
 	// Public Endpoints
 	pubRoutes := app.Group("/user")
-	pubRoutes.Post("/register", handler.Register)
-	pubRoutes.Post("/login", handler.Login)
+	pubRoutes.Post("/register", limiter.Limit(limiter.Sms, ratelimit.ByIP, ratelimit.ByPhone(helper.NewPhoneRules(rh.Config.Notifications.Sms))), handler.Register)
+	pubRoutes.Post("/login", limiter.Limit(limiter.Auth, ratelimit.ByIP), handler.Login)
 
 	// Private Endpoints
 	pvtRoutes := app.Group("/", rh.Auth.Authorize)
-	pvtRoutes.Get("/verify", handler.GetVerificationCode)
-	pvtRoutes.Post("/verify", handler.Verify)
+	pvtRoutes.Get("/verify", limiter.Limit(limiter.Sms, ratelimit.ByUser), handler.GetVerificationCode)
+	pvtRoutes.Post("/verify", limiter.Limit(limiter.Auth, ratelimit.ByUser), handler.Verify)
 	pvtRoutes.Get("/profile", handler.GetProfile)
 	pvtRoutes.Post("/profile", handler.CreateProfile)
-	pvtRoutes.Post("/cart", handler.AddToCart)
-	pvtRoutes.Get("/cart", handler.GetCart)
-	pvtRoutes.Post("/order", handler.CreateOrder)
-	pvtRoutes.Get("/order", handler.GetOrders)
-	pvtRoutes.Get("/order/:id", handler.GetOrder)
+	pvtRoutes.Patch("/profile", handler.UpdateProfile)
+	pvtRoutes.Get("/profile/addresses", handler.GetAddresses)
+	pvtRoutes.Post("/profile/addresses", handler.AddAddress)
+	pvtRoutes.Patch("/profile/addresses/:id", handler.UpdateAddress)
+	pvtRoutes.Delete("/profile/addresses/:id", handler.DeleteAddress)
 	pvtRoutes.Post("/become-seller", handler.BecomeSeller)
 }
 
 func (h *UserHandler) Register(ctx *fiber.Ctx) error {
 	user := dto.UserSignup{}
-	err := ctx.BodyParser(&user)
-
-	if err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
-			"message": "please provide valid input",
-		})
+	if err := rest.ParseBody(ctx, &user); err != nil {
+		return rest.ErrorResponse(ctx, err)
 	}
 
-	token, err := h.svc.SignUp(user)
+	token, err := h.svc.SignUp(ctx.UserContext(), user)
 
 	if err != nil {
-		log.Println(err.Error())
-		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
-			"message": err.Error(),
-		})
+		return rest.ErrorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
@@ -75,23 +73,14 @@ func (h *UserHandler) Register(ctx *fiber.Ctx) error {
 
 func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 	user := dto.UserLogin{}
-	err := ctx.BodyParser(&user)
-
-	if err != nil {
-		log.Println(err.Error())
-
-		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
-			"message": "please provide valid input",
-			"error":   err.Error(),
-		})
+	if err := rest.ParseBody(ctx, &user); err != nil {
+		return rest.ErrorResponse(ctx, err)
 	}
 
-	token, err := h.svc.Login(user.Email, user.Password)
+	token, err := h.svc.Login(ctx.UserContext(), user.Email, user.Password)
 
 	if err != nil {
-		return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
-			"message": err.Error(),
-		})
+		return rest.ErrorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
@@ -103,13 +92,10 @@ func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 func (h *UserHandler) GetVerificationCode(ctx *fiber.Ctx) error {
 	user := h.svc.Auth.GetCurrentUser(ctx)
 
-	err := h.svc.GetVerificationCode(user)
+	err := h.svc.GetVerificationCode(ctx.UserContext(), user)
 
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
-			"message": "could not get verification code",
-			"error":   err.Error(),
-		})
+		return rest.ErrorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
@@ -122,20 +108,14 @@ func (h *UserHandler) Verify(ctx *fiber.Ctx) error {
 
 	var req dto.VerificationCodeInput
 
-	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
-			"message": "please provide valid input",
-			"error":   err.Error(),
-		})
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
 	}
 
-	err := h.svc.VerifyCode(user.ID, req.Code)
+	err := h.svc.VerifyCode(ctx.UserContext(), user.ID, req.Code)
 
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
-			"message": "could not verify code",
-			"error":   err.Error(),
-		})
+		return rest.ErrorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
@@ -144,47 +124,126 @@ func (h *UserHandler) Verify(ctx *fiber.Ctx) error {
 }
 
 func (h *UserHandler) CreateProfile(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	req := dto.ProfileInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.CreateProfile(ctx.UserContext(), user.ID, req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "CreateProfile",
+		"message": "profile created successfully",
 	})
 }
 
 func (h *UserHandler) GetProfile(ctx *fiber.Ctx) error {
 	user := h.svc.Auth.GetCurrentUser(ctx)
 
+	profile, err := h.svc.GetProfile(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
 		"message": "success",
-		"user":    user,
+		"user":    dto.NewUserResponse(*profile),
 	})
 }
 
-func (h *UserHandler) AddToCart(ctx *fiber.Ctx) error {
+func (h *UserHandler) UpdateProfile(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	req := dto.ProfileInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	profile, err := h.svc.UpdateProfile(ctx.UserContext(), user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "AddToCart",
+		"message": "profile updated successfully",
+		"user":    dto.NewUserResponse(*profile),
 	})
 }
 
-func (h *UserHandler) GetCart(ctx *fiber.Ctx) error {
+func (h *UserHandler) GetAddresses(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	addresses, err := h.svc.GetAddresses(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "GetCart",
+		"message":   "success",
+		"addresses": dto.NewAddressResponses(addresses),
 	})
 }
 
-func (h *UserHandler) CreateOrder(ctx *fiber.Ctx) error {
-	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "CreateOrder",
+func (h *UserHandler) AddAddress(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	req := dto.AddressInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	address, err := h.svc.AddAddress(ctx.UserContext(), user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"message": "address added",
+		"address": dto.NewAddressResponse(*address),
 	})
 }
 
-func (h *UserHandler) GetOrders(ctx *fiber.Ctx) error {
+func (h *UserHandler) UpdateAddress(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.AddressUpdateInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	address, err := h.svc.UpdateAddress(ctx.UserContext(), user.ID, id, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "GetOrders",
+		"message": "address updated",
+		"address": dto.NewAddressResponse(*address),
 	})
 }
 
-func (h *UserHandler) GetOrder(ctx *fiber.Ctx) error {
+func (h *UserHandler) DeleteAddress(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteAddress(ctx.UserContext(), user.ID, id); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-		"message": "GetOrderById",
+		"message": "address deleted",
 	})
 }
 
@@ -192,25 +251,19 @@ func (h *UserHandler) BecomeSeller(ctx *fiber.Ctx) error {
 	user := h.svc.Auth.GetCurrentUser(ctx)
 
 	req := dto.SellerInput{}
-	err := ctx.BodyParser(&req)
-
-	if err != nil {
-		return ctx.Status(400).JSON(&fiber.Map{
-			"message": "request parameters are not valid",
-		})
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
 	}
 
-	token, err := h.svc.BecomeSeller(user.ID, req)
+	token, seller, err := h.svc.BecomeSeller(ctx.UserContext(), user.ID, req)
 
 	if err != nil {
-		return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
-			"message": "fail to become seller",
-			"error":   err.Error(),
-		})
+		return rest.ErrorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
 		"message": "become seller",
 		"token":   token,
+		"seller":  dto.NewSellerResponse(*seller),
 	})
 }
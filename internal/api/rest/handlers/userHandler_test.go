@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"io"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	testPassword = "correct-horse"
+	leakedCode   = 731904
+)
+
+// userRepo serves one user whose credentials and verification state are
+// all set, as a handler serializing the domain user would show them.
+type userRepo struct {
+	repository.UserRepository
+	user domain.User
+}
+
+func (r userRepo) FindUser(ctx context.Context, email string) (domain.User, error) {
+	return r.user, nil
+}
+
+func (r userRepo) FindUserById(ctx context.Context, id uint) (domain.User, error) {
+	return r.user, nil
+}
+
+func (r userRepo) FindUserWithAddresses(ctx context.Context, id uint) (domain.User, error) {
+	return r.user, nil
+}
+
+func (r userRepo) UpdateUser(ctx context.Context, id uint, usr domain.User) (domain.User, error) {
+	updated := r.user
+	updated.FirstName = usr.FirstName
+	updated.LastName = usr.LastName
+	updated.Phone = usr.Phone
+	updated.UserType = usr.UserType
+	return updated, nil
+}
+
+func (r userRepo) CreateBankAccount(ctx context.Context, e domain.BankAccount) error {
+	return nil
+}
+
+type unitOfWork struct {
+	repos repository.Repositories
+}
+
+func (u unitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return fn(u.repos)
+}
+
+func newTestUserHandler(t *testing.T) (*UserHandler, domain.User) {
+	t.Helper()
+
+	cfg := config.Defaults()
+	auth := helper.Auth{Secret: "test-secret", TokenTTL: time.Hour}
+
+	hash, err := auth.CreateHashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := domain.User{
+		ID:        7,
+		FirstName: "Ana",
+		LastName:  "Silva",
+		Email:     "ana@example.com",
+		Phone:     "+351912345678",
+		Password:  hash,
+		Code:      leakedCode,
+		Expiry:    time.Date(2031, 2, 3, 4, 5, 6, 0, time.UTC),
+		Verified:  true,
+		UserType:  domain.BUYER,
+		Addresses: []domain.Address{{ID: 1, UserID: 7, AddressLine1: "Rua 1", City: "Lisboa", Country: "PT"}},
+	}
+
+	repo := userRepo{user: user}
+	handler := &UserHandler{svc: service.UserService{
+		Repo:   repo,
+		Tx:     unitOfWork{repos: repository.Repositories{User: repo}},
+		Auth:   auth,
+		Config: cfg,
+	}}
+
+	return handler, user
+}
+
+// call runs handler for a request from user and returns the decoded body.
+func call(t *testing.T, handler fiber.Handler, user domain.User, body string) map[string]any {
+	t.Helper()
+
+	app := fiber.New()
+	app.Post("/", func(ctx *fiber.Ctx) error {
+		ctx.Locals("user", domain.User{ID: user.ID, Email: user.Email, UserType: user.UserType})
+		return ctx.Next()
+	}, handler)
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("status %d: %s", res.StatusCode, raw)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+
+	return decoded
+}
+
+// assertShape fails unless got is a JSON object with the same keys as want,
+// a response DTO. The DTOs are checked for secrets in package dto, so this
+// makes sure the handler sends one of them rather than the domain type.
+func assertShape(t *testing.T, name string, got any, want any) {
+	t.Helper()
+
+	obj, ok := got.(map[string]any)
+	if !ok {
+		t.Fatalf("%s is %T, want an object", name, got)
+	}
+
+	raw, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wantObj map[string]any
+	if err := json.Unmarshal(raw, &wantObj); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotKeys, wantKeys := keys(obj), keys(wantObj); !slices.Equal(gotKeys, wantKeys) {
+		t.Errorf("%s has keys %v, want %v", name, gotKeys, wantKeys)
+	}
+}
+
+func keys(m map[string]any) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func TestLoginResponse(t *testing.T) {
+	h, user := newTestUserHandler(t)
+
+	res := call(t, h.Login, user, `{"email":"ana@example.com","password":"`+testPassword+`"}`)
+	assertShape(t, "login response", res, map[string]any{"message": "", "token": ""})
+	if token, _ := res["token"].(string); token == "" {
+		t.Errorf("no token in %v", res)
+	}
+}
+
+func TestGetProfileResponse(t *testing.T) {
+	h, user := newTestUserHandler(t)
+
+	res := call(t, h.GetProfile, user, "")
+	assertShape(t, "profile response", res, map[string]any{"message": "", "user": nil})
+	assertShape(t, "user", res["user"], dto.NewUserResponse(user))
+}
+
+func TestBecomeSellerResponse(t *testing.T) {
+	h, user := newTestUserHandler(t)
+
+	body := `{"first_name":"Ana","last_name":"Silva","phone_number":"+351912345678",` +
+		`"bank_account_number":123456789,"swift_code":"BCOMPTPL","payment_type":"iban"}`
+
+	res := call(t, h.BecomeSeller, user, body)
+	assertShape(t, "become seller response", res, map[string]any{"message": "", "token": "", "seller": nil})
+	assertShape(t, "seller", res["seller"], dto.NewSellerResponse(user))
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"go-ecommerce-app/internal/api/rest"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"go-ecommerce-app/pkg/webhooks"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	svc service.WebhookService
+}
+
+func SetupWebhookRoutes(rh *rest.RestHandler) {
+	app := rh.App
+
+	// Create an instance of webhook service and inject to handler
+	svc := NewWebhookService(rh)
+
+	handler := WebhookHandler{
+		svc: svc,
+	}
+
+	// Private Endpoints
+	selRoutes := app.Group("/seller/webhooks", rh.Auth.AuthorizeSeller)
+	selRoutes.Get("/", handler.GetEndpoints)
+	selRoutes.Post("/", handler.CreateEndpoint)
+	selRoutes.Get("/:id", handler.GetEndpoint)
+	selRoutes.Patch("/:id", handler.UpdateEndpoint)
+	selRoutes.Delete("/:id", handler.DeleteEndpoint)
+	selRoutes.Post("/:id/test", handler.SendTestEvent)
+	selRoutes.Get("/:id/deliveries", handler.GetDeliveries)
+	selRoutes.Get("/:id/deliveries/:deliveryId", handler.GetDelivery)
+	selRoutes.Post("/:id/deliveries/:deliveryId/redeliver", handler.Redeliver)
+}
+
+// NewWebhookService builds the webhook service shared by the seller routes
+// and the background delivery worker.
+func NewWebhookService(rh *rest.RestHandler) service.WebhookService {
+	return service.WebhookService{
+		Repo:   repository.NewWebhookRepository(rh.DB),
+		Sender: webhooks.NewSender(10 * time.Second),
+		Auth:   rh.Auth,
+		Config: rh.Config,
+	}
+}
+
+func (h WebhookHandler) CreateEndpoint(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	req := dto.WebhookEndpointInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	endpoint, err := h.svc.CreateEndpoint(ctx.UserContext(), user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	// the secret is only returned once, when the endpoint is created
+	return rest.SuccessResponse(ctx, "webhook endpoint created", &fiber.Map{
+		"endpoint": endpoint,
+		"secret":   endpoint.Secret,
+	})
+}
+
+func (h WebhookHandler) GetEndpoints(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	endpoints, err := h.svc.GetEndpoints(ctx.UserContext(), user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook endpoints", endpoints)
+}
+
+func (h WebhookHandler) GetEndpoint(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	endpoint, err := h.svc.GetEndpoint(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook endpoint", endpoint)
+}
+
+func (h WebhookHandler) UpdateEndpoint(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	req := dto.WebhookEndpointUpdateInput{}
+	if err := rest.ParseBody(ctx, &req); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	endpoint, err := h.svc.UpdateEndpoint(ctx.UserContext(), id, user.ID, req)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook endpoint updated", endpoint)
+}
+
+func (h WebhookHandler) DeleteEndpoint(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	if err := h.svc.DeleteEndpoint(ctx.UserContext(), id, user.ID); err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook endpoint deleted", nil)
+}
+
+func (h WebhookHandler) SendTestEvent(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	delivery, err := h.svc.SendTestEvent(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "test event sent", delivery)
+}
+
+func (h WebhookHandler) GetDeliveries(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	deliveries, err := h.svc.GetDeliveries(ctx.UserContext(), id, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook deliveries", deliveries)
+}
+
+func (h WebhookHandler) GetDelivery(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	deliveryId, err := rest.ParamId(ctx, "deliveryId")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	delivery, err := h.svc.GetDelivery(ctx.UserContext(), id, deliveryId, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook delivery", delivery)
+}
+
+func (h WebhookHandler) Redeliver(ctx *fiber.Ctx) error {
+	user := h.svc.Auth.GetCurrentUser(ctx)
+
+	id, err := rest.ParamId(ctx, "id")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	deliveryId, err := rest.ParamId(ctx, "deliveryId")
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	delivery, err := h.svc.Redeliver(ctx.UserContext(), id, deliveryId, user.ID)
+	if err != nil {
+		return rest.ErrorResponse(ctx, err)
+	}
+
+	return rest.SuccessResponse(ctx, "webhook redelivered", delivery)
+}
@@ -4,12 +4,20 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go-ecommerce-app/config"
 	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/ratelimit"
+	"go-ecommerce-app/pkg/notifications"
+	"go-ecommerce-app/pkg/payments"
+	"go-ecommerce-app/pkg/storage"
 	"gorm.io/gorm"
 )
 
 type RestHandler struct {
-	App    *fiber.App
-	DB     *gorm.DB
-	Auth   helper.Auth
-	Config config.AppConfig
+	App      *fiber.App
+	DB       *gorm.DB
+	Auth     helper.Auth
+	Notify   notifications.NotificationClient
+	Payments payments.Provider
+	Storage  storage.Storage
+	Limiter  *ratelimit.Limiter
+	Config   config.AppConfig
 }
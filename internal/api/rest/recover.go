@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Recover turns a panic in a later handler into an internal error response,
+// logged with its stack, so one bad request can't take the server down.
+func Recover(ctx *fiber.Ctx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = InternalError(ctx, fmt.Errorf("panic: %v\n%s", r, debug.Stack()))
+		}
+	}()
+
+	return ctx.Next()
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"errors"
+	"go-ecommerce-app/internal/helper"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var ErrInvalidBody = errors.New("please provide valid input")
+
+// ParseBody decodes the request body into out and validates it. The returned
+// error is meant to be passed to ErrorResponse.
+func ParseBody(ctx *fiber.Ctx, out any) error {
+	if err := ctx.BodyParser(out); err != nil {
+		return ErrInvalidBody
+	}
+
+	return helper.ValidateStruct(out)
+}
+
+// ParamId reads a positive numeric route parameter.
+func ParamId(ctx *fiber.Ctx, key string) (uint, error) {
+	id, err := ctx.ParamsInt(key)
+	if err != nil || id <= 0 {
+		return 0, helper.ValidationErrors{key: "must be a positive integer"}
+	}
+
+	return uint(id), nil
+}
+
+// AcceptCurrencyHeader lists the currencies a client wants prices in, most
+// preferred first, e.g. "USD, EUR".
+const AcceptCurrencyHeader = "Accept-Currency"
+
+// Currencies reads the currencies a client asked prices in: the currency
+// query parameter, which is explicit, or else the Accept-Currency header.
+func Currencies(ctx *fiber.Ctx) ([]string, bool) {
+	if c := strings.TrimSpace(ctx.Query("currency")); c != "" {
+		return []string{c}, true
+	}
+
+	var codes []string
+	for _, part := range strings.Split(ctx.Get(AcceptCurrencyHeader), ",") {
+		// quality values are accepted but the listed order wins
+		code, _, _ := strings.Cut(part, ";")
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes, false
+}
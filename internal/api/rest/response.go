@@ -1,16 +1,95 @@
 package rest
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/logging"
+	"go-ecommerce-app/internal/tracing"
+	"log/slog"
 	"net/http"
 )
 
-func ErrorMessage(ctx *fiber.Ctx, status int, err error) error {
-	return ctx.Status(status).JSON(err.Error())
+// ErrorBody is the JSON shape of every error response. Code is stable and
+// meant for programmatic handling; Message is for humans. RequestId is set
+// on internal errors so a report can be matched with the server logs.
+type ErrorBody struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	RequestId string            `json:"request_id,omitempty"`
+}
+
+var errorStatuses = []struct {
+	kind   error
+	status int
+}{
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrValidation, http.StatusUnprocessableEntity},
+	{domain.ErrUnauthorized, http.StatusUnauthorized},
+	{domain.ErrForbidden, http.StatusForbidden},
+	{domain.ErrRateLimited, http.StatusTooManyRequests},
+	{domain.ErrUnavailable, http.StatusServiceUnavailable},
+}
+
+// ErrorResponse maps err to an HTTP status and writes the error body. Typed
+// domain errors keep their code and message, request validation failures
+// list the offending fields, and anything else is logged and reported as an
+// opaque internal error.
+func ErrorResponse(ctx *fiber.Ctx, err error) error {
+	var fieldErrs helper.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return ctx.Status(http.StatusUnprocessableEntity).JSON(ErrorBody{
+			Code:    "validation_failed",
+			Message: "validation failed",
+			Errors:  fieldErrs,
+		})
+	}
+
+	if errors.Is(err, ErrInvalidBody) {
+		return ctx.Status(http.StatusBadRequest).JSON(ErrorBody{
+			Code:    "invalid_body",
+			Message: ErrInvalidBody.Error(),
+		})
+	}
+
+	var appErr *domain.Error
+	if errors.As(err, &appErr) {
+		if appErr.Err != nil {
+			tracing.RecordError(ctx.UserContext(), appErr.Err)
+			slog.WarnContext(ctx.UserContext(), "request failed", "error_code", appErr.Code, "error", appErr.Err)
+		}
+
+		return ctx.Status(statusFor(appErr)).JSON(ErrorBody{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		})
+	}
+
+	return InternalError(ctx, err)
+}
+
+func statusFor(err error) int {
+	for _, s := range errorStatuses {
+		if errors.Is(err, s.kind) {
+			return s.status
+		}
+	}
+
+	return http.StatusInternalServerError
 }
 
 func InternalError(ctx *fiber.Ctx, err error) error {
-	return ctx.Status(http.StatusInternalServerError).JSON(err.Error())
+	slog.ErrorContext(ctx.UserContext(), "internal error", "error", err)
+	tracing.RecordError(ctx.UserContext(), err)
+
+	return ctx.Status(http.StatusInternalServerError).JSON(ErrorBody{
+		Code:      "internal_error",
+		Message:   "internal server error",
+		RequestId: logging.RequestId(ctx.UserContext()),
+	})
 }
 
 func SuccessResponse(ctx *fiber.Ctx, msg string, data interface{}) error {
@@ -1,53 +1,181 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"go-ecommerce-app/config"
 	"go-ecommerce-app/internal/api/rest"
 	"go-ecommerce-app/internal/api/rest/handlers"
-	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/database"
+	"go-ecommerce-app/internal/database/migrations"
 	"go-ecommerce-app/internal/helper"
-	"log"
+	"go-ecommerce-app/internal/logging"
+	"go-ecommerce-app/internal/metrics"
+	"go-ecommerce-app/internal/ratelimit"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/notifications"
+	"go-ecommerce-app/pkg/payments"
+	"go-ecommerce-app/pkg/storage"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
-func StartServer(config config.AppConfig) {
-	app := fiber.New()
+// StartServer runs the http server and background workers until SIGINT or
+// SIGTERM, then shuts everything down in order. It returns an error if the
+// server could not start or did not shut down cleanly.
+func StartServer(config config.AppConfig) error {
+	app := fiber.New(fiber.Config{
+		ReadTimeout:  config.Server.ReadTimeout,
+		WriteTimeout: config.Server.WriteTimeout,
+		IdleTimeout:  config.Server.IdleTimeout,
+		BodyLimit:    config.Server.BodyLimit,
+	})
 
-	db, err := gorm.Open(postgres.Open(config.Dsn), &gorm.Config{})
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		return errors.New("tracing setup error: " + err.Error())
+	}
+
+	db, err := database.Connect(config.Database)
 
 	if err != nil {
-		log.Fatalf("database connection error %v\n", err)
+		return errors.New("database connection error: " + err.Error())
 	}
 
-	log.Println("database connected")
+	slog.Info("database connected")
 
-	// Run migration
-	err = db.AutoMigrate(&domain.User{}, &domain.BankAccount{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("database migration error %v\n", err)
+		return err
+	}
+
+	if err := metrics.InstrumentGorm(db); err != nil {
+		return err
+	}
+
+	if err := tracing.InstrumentGorm(db); err != nil {
+		return err
 	}
-	log.Println("database migration success")
 
-	auth := helper.SetupAuth(config.AppSecret)
+	if err := metrics.RegisterDBStats(sqlDB); err != nil {
+		return err
+	}
+
+	// Refuse to run against a schema older than this binary expects
+	migrator, err := migrations.New(db)
+	if err == nil {
+		err = migrator.CheckCurrent()
+	}
+
+	if err != nil {
+		sqlDB.Close()
+		return errors.New("database migration error: " + err.Error())
+	}
+	slog.Info("database schema is up to date")
+
+	media, err := storage.New(config.Media)
+	if err != nil {
+		sqlDB.Close()
+		return errors.New("media storage error: " + err.Error())
+	}
+
+	auth := helper.SetupAuth(config.Auth)
 
 	rh := &rest.RestHandler{
-		App:    app,
-		DB:     db,
-		Auth:   auth,
-		Config: config,
+		App:      app,
+		DB:       db,
+		Auth:     auth,
+		Notify:   notifications.NewNotificationClient(config),
+		Payments: payments.NewProvider(config.Payments),
+		Storage:  media,
+		Limiter:  ratelimit.New(config.RateLimits, ratelimit.NewMemoryStore()),
+		Config:   config,
 	}
 
+	workers := newWorkerGroup()
+	var draining atomic.Bool
+
+	// Tracing, request ids, metrics and probes go first so the auth middleware
+	// on "/" doesn't cover them. Panics are recovered inside them, so they
+	// see and count an internal error instead.
+	app.Use(tracing.Middleware)
+	app.Use(logging.Middleware)
+	app.Use(metrics.Middleware)
+	app.Use(rest.Recover)
+	app.Get("/metrics", metrics.Handler())
+
+	handlers.SetupHealthRoutes(rh,
+		livenessChecks(config, workers),
+		readinessChecks(config, sqlDB, migrator, &draining),
+	)
+
 	setupRoutes(rh)
 
-	app.Listen(config.ServerPort)
+	// Background workers
+	workers.Go("webhooks", handlers.NewWebhookService(rh).RunWorker)
+	workers.Go("reservations", handlers.NewOrderService(rh).RunSweeper)
+	workers.Go("media", handlers.NewMediaService(rh).RunCleanup)
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(config.Server.Addr())
+	}()
+
+	select {
+	case err = <-listenErr:
+		slog.Error("http server stopped", "error", err)
+	case <-signals.Done():
+		slog.Info("shutdown signal received, draining")
+	}
+
+	draining.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+	defer cancel()
+
+	shutdownErr := shutdown(ctx, []shutdownStep{
+		{"http server", app.ShutdownWithContext},
+		{"background workers", workers.Stop},
+		{"notification client", rh.Notify.Close},
+		{"tracer provider", shutdownTracing},
+		{"database pool", func(context.Context) error { return sqlDB.Close() }},
+	})
+
+	return errors.Join(err, shutdownErr)
 }
 
 func setupRoutes(rh *rest.RestHandler) {
+	// Default rate limit for everything registered below
+	rh.App.Use(rh.Limiter.Limit(rh.Limiter.Default, ratelimit.ByIP))
+
+	// Catalog, whose public routes must precede the authorized "/" group
+	handlers.SetupCatalogRoutes(rh)
+	// Cart, orders and the payment webhook, ahead of the authorized "/" group
+	handlers.SetupOrderRoutes(rh)
+	// Product images and, with local storage, the files themselves
+	handlers.SetupMediaRoutes(rh)
 	// User handlers
 	handlers.SetupUserRoutes(rh)
 	// Transactions
-	// Catalog
+	handlers.SetupTransactionRoutes(rh)
+	// Seller webhooks
+	handlers.SetupWebhookRoutes(rh)
+	// Currency exchange rates
+	handlers.SetupCurrencyRoutes(rh)
+	// Tax rates and classes
+	handlers.SetupTaxRoutes(rh)
+	// Shipping zones and methods
+	handlers.SetupShippingRoutes(rh)
+	// Seller fulfilments and shipments
+	handlers.SetupFulfilmentRoutes(rh)
+	// Coupons
+	handlers.SetupCouponRoutes(rh)
 }
@@ -0,0 +1,79 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set at build time with
+//
+//	go build -ldflags "-X go-ecommerce-app/internal/buildinfo.Version=v1.2.3 \
+//	  -X go-ecommerce-app/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X go-ecommerce-app/internal/buildinfo.BuildTime=$(date -u +%FT%TZ)"
+//
+// When they are not set, the vcs information embedded by the go tool is used.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version      string            `json:"version"`
+	Commit       string            `json:"commit"`
+	BuildTime    string            `json:"build_time"`
+	Modified     bool              `json:"modified"`
+	GoVersion    string            `json:"go_version"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// dependencies lists the modules whose versions are reported.
+var dependencies = []string{
+	"github.com/gofiber/fiber/v2",
+	"gorm.io/gorm",
+	"gorm.io/driver/postgres",
+	"github.com/jackc/pgx/v5",
+	"github.com/golang-jwt/jwt/v5",
+	"github.com/twilio/twilio-go",
+}
+
+func Get() Info {
+	info := Info{
+		Version:      Version,
+		Commit:       Commit,
+		BuildTime:    BuildTime,
+		GoVersion:    runtime.Version(),
+		Dependencies: map[string]string{},
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+
+	for _, dep := range bi.Deps {
+		for _, name := range dependencies {
+			if strings.EqualFold(dep.Path, name) {
+				info.Dependencies[dep.Path] = dep.Version
+			}
+		}
+	}
+
+	return info
+}
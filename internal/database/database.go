@@ -0,0 +1,35 @@
+package database
+
+import (
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/logging"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Connect opens the postgres connection shared by the server and the
+// command line tools, with the pool sized from cfg.
+func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.Dsn), &gorm.Config{
+		// surface unique violations as gorm.ErrDuplicatedKey
+		TranslateError: true,
+		Logger:         logging.NewGormLogger(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db, nil
+}
@@ -0,0 +1,348 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// SourceDir is where `migrate create` writes new files, relative to the
+// repository root. They are embedded into the binary on the next build.
+const SourceDir = "internal/database/migrations/sql"
+
+// advisoryLockId serializes migrators running against the same database.
+const advisoryLockId = 7_240_815_311
+
+var filePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// appliedMigration is a row of the schema_migrations table.
+type appliedMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := filePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	return m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version     BIGINT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		applied_at  TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+	)`).Error
+}
+
+func (m *Migrator) applied() (map[int64]appliedMigration, error) {
+	var rows []appliedMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]appliedMigration, len(rows))
+	for _, r := range rows {
+		res[r.Version] = r
+	}
+
+	return res, nil
+}
+
+// withLock holds a postgres advisory lock on a dedicated connection while fn
+// runs on that connection, so two deploys can't migrate at the same time.
+func (m *Migrator) withLock(fn func(locked *Migrator) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", advisoryLockId).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", advisoryLockId)
+
+		locked := &Migrator{db: conn, migrations: m.migrations}
+		if err := locked.ensureTable(); err != nil {
+			return err
+		}
+
+		return fn(locked)
+	})
+}
+
+// WithContext returns a migrator whose queries are bound to ctx.
+func (m *Migrator) WithContext(ctx context.Context) *Migrator {
+	return &Migrator{db: m.db.WithContext(ctx), migrations: m.migrations}
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+func (m *Migrator) Up() ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(func(locked *Migrator) error {
+		applied, err := locked.applied()
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+
+			err := locked.db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.Up).Error; err != nil {
+					return err
+				}
+
+				return tx.Create(&appliedMigration{
+					Version:   mig.Version,
+					Name:      mig.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the given number of most recently applied migrations.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(func(locked *Migrator) error {
+		applied, err := locked.applied()
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+
+			err := locked.db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.Down).Error; err != nil {
+					return err
+				}
+
+				return tx.Delete(&appliedMigration{}, "version = ?", mig.Version).Error
+			})
+
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every known migration with the time it was applied, if any.
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			at := a.AppliedAt
+			st.AppliedAt = &at
+		}
+		res = append(res, st)
+	}
+
+	return res, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending() ([]Migration, error) {
+	var exists bool
+	err := m.db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return m.migrations, nil
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+
+	return pending, nil
+}
+
+var ErrOutdatedSchema = errors.New("database schema is outdated, run `migrate up`")
+
+// CheckCurrent fails with ErrOutdatedSchema when any migration is pending.
+func (m *Migrator) CheckCurrent() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for _, p := range pending {
+			names = append(names, fmt.Sprintf("%04d_%s", p.Version, p.Name))
+		}
+		return fmt.Errorf("%w (pending: %s)", ErrOutdatedSchema, strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// Create writes an empty up/down pair for the next version into dir.
+func (m *Migrator) Create(dir string, name string) (string, string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return "", "", errors.New("migration name may only contain letters, digits and underscores")
+	}
+
+	// files on disk may be newer than the ones embedded in this binary
+	onDisk, err := load(os.DirFS(dir), ".")
+	if err != nil {
+		return "", "", err
+	}
+
+	var next int64 = 1
+	for _, list := range [][]Migration{m.migrations, onDisk} {
+		if n := len(list); n > 0 && list[n-1].Version >= next {
+			next = list[n-1].Version + 1
+		}
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", next, name))
+	up, down := base+".up.sql", base+".down.sql"
+
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- revert "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS addresses;
+DROP TABLE IF EXISTS bank_accounts;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,47 @@
+-- Tables may already exist on databases created by the old AutoMigrate,
+-- so this baseline only creates what is missing.
+
+CREATE TABLE IF NOT EXISTS users (
+    id          BIGSERIAL PRIMARY KEY,
+    first_name  TEXT,
+    last_name   TEXT,
+    email       TEXT NOT NULL,
+    phone       TEXT,
+    password    TEXT,
+    code        BIGINT,
+    expiry      TIMESTAMPTZ,
+    verified    BOOLEAN DEFAULT false,
+    user_type   TEXT DEFAULT 'buyer',
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS bank_accounts (
+    id                   BIGSERIAL PRIMARY KEY,
+    user_id              BIGINT REFERENCES users (id),
+    bank_account_number  BIGINT NOT NULL,
+    swift_code           TEXT,
+    payment_type         TEXT,
+    created_at           TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at           TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_bank_account_number ON bank_accounts (bank_account_number);
+
+CREATE TABLE IF NOT EXISTS addresses (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    address_line1     TEXT,
+    address_line2     TEXT,
+    city              TEXT,
+    post_code         BIGINT,
+    country           TEXT,
+    default_shipping  BOOLEAN DEFAULT false,
+    default_billing   BOOLEAN DEFAULT false,
+    created_at        TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at        TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON addresses (user_id);
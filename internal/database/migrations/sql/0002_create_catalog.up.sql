@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id             BIGSERIAL PRIMARY KEY,
+    name           TEXT,
+    parent_id      BIGINT,
+    image_url      TEXT,
+    display_order  BIGINT,
+    created_at     TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at     TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS products (
+    id           BIGSERIAL PRIMARY KEY,
+    name         TEXT,
+    description  TEXT,
+    category_id  BIGINT REFERENCES categories (id),
+    image_url    TEXT,
+    price        NUMERIC,
+    user_id      BIGINT REFERENCES users (id),
+    stock        BIGINT,
+    created_at   TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at   TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_name ON products (name);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
+CREATE INDEX IF NOT EXISTS idx_products_user_id ON products (user_id);
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users (id),
+    url          TEXT NOT NULL,
+    description  TEXT,
+    secret       TEXT NOT NULL,
+    events       TEXT,
+    active       BOOLEAN DEFAULT true,
+    created_at   TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at   TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user_id ON webhook_endpoints (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                  BIGSERIAL PRIMARY KEY,
+    endpoint_id         BIGINT NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    event_type          TEXT,
+    payload             TEXT,
+    status              TEXT DEFAULT 'pending',
+    attempts            BIGINT DEFAULT 0,
+    last_response_code  BIGINT,
+    last_error          TEXT,
+    next_attempt_at     TIMESTAMPTZ,
+    delivered_at        TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at          TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id ON webhook_deliveries (endpoint_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id             BIGSERIAL PRIMARY KEY,
+    delivery_id    BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    response_code  BIGINT,
+    error          TEXT,
+    duration_ms    BIGINT,
+    created_at     TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sms_messages (
+    id          BIGSERIAL PRIMARY KEY,
+    phone       TEXT NOT NULL,
+    purpose     TEXT,
+    status      TEXT DEFAULT 'reserved',
+    error       TEXT,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_sms_messages_phone ON sms_messages (phone, created_at);
+CREATE INDEX IF NOT EXISTS idx_sms_messages_created_at ON sms_messages (created_at);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS cart_items;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_reserved_check;
+ALTER TABLE products DROP COLUMN IF EXISTS reserved;
+ALTER TABLE products ALTER COLUMN stock DROP NOT NULL;
+ALTER TABLE products ALTER COLUMN stock DROP DEFAULT;
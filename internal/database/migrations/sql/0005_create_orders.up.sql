@@ -0,0 +1,66 @@
+-- Stock held by unpaid orders. The check makes overselling impossible even
+-- if a bug skipped the conditional updates in the repository.
+UPDATE products SET stock = 0 WHERE stock IS NULL;
+ALTER TABLE products ALTER COLUMN stock SET DEFAULT 0;
+ALTER TABLE products ALTER COLUMN stock SET NOT NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reserved BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT products_reserved_check CHECK (reserved >= 0 AND reserved <= stock);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    product_id  BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    qty         BIGINT NOT NULL CHECK (qty > 0),
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_user_product ON cart_items (user_id, product_id);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users (id),
+    reference   TEXT NOT NULL,
+    status      TEXT DEFAULT 'pending_payment',
+    amount      NUMERIC,
+    currency    TEXT,
+    payment_id  TEXT,
+    expires_at  TIMESTAMPTZ,
+    paid_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_reference ON orders (reference);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_payment_id ON orders (payment_id);
+CREATE INDEX IF NOT EXISTS idx_orders_pending ON orders (status, expires_at);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id  BIGINT NOT NULL REFERENCES products (id),
+    seller_id   BIGINT NOT NULL REFERENCES users (id),
+    name        TEXT,
+    image_url   TEXT,
+    price       NUMERIC,
+    qty         BIGINT NOT NULL CHECK (qty > 0),
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_seller_id ON order_items (seller_id);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id  BIGINT NOT NULL REFERENCES products (id),
+    qty         BIGINT NOT NULL CHECK (qty > 0),
+    status      TEXT DEFAULT 'active',
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_order_id ON stock_reservations (order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_active ON stock_reservations (status, expires_at);
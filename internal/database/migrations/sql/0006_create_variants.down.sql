@@ -0,0 +1,16 @@
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS variant_id;
+
+DROP INDEX IF EXISTS idx_order_items_variant_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_name;
+ALTER TABLE order_items DROP COLUMN IF EXISTS sku;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+-- Variant lines can't be told apart without variant_id
+DELETE FROM cart_items WHERE variant_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_cart_items_user_variant;
+DROP INDEX IF EXISTS idx_cart_items_user_product;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_user_product ON cart_items (user_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
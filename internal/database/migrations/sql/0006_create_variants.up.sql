@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS product_options (
+    id          BIGSERIAL PRIMARY KEY,
+    product_id  BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    position    BIGINT NOT NULL DEFAULT 0,
+    "values"    TEXT,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_options_product_name ON product_options (product_id, lower(name));
+
+-- Variants hold their own stock, with the same no-oversell check as products.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id           BIGSERIAL PRIMARY KEY,
+    product_id   BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    sku          TEXT NOT NULL,
+    options      TEXT,
+    options_key  TEXT NOT NULL,
+    price        NUMERIC,
+    stock        BIGINT NOT NULL DEFAULT 0,
+    reserved     BIGINT NOT NULL DEFAULT 0,
+    image_url    TEXT,
+    active       BOOLEAN DEFAULT true,
+    created_at   TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at   TIMESTAMPTZ DEFAULT current_timestamp,
+    CONSTRAINT product_variants_reserved_check CHECK (reserved >= 0 AND reserved <= stock)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants (sku);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_options ON product_variants (product_id, options_key);
+
+-- A cart holds one line per product, or per variant for products that have
+-- them.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id BIGINT REFERENCES product_variants (id) ON DELETE CASCADE;
+DROP INDEX IF EXISTS idx_cart_items_user_product;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_user_product ON cart_items (user_id, product_id) WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_user_variant ON cart_items (user_id, variant_id) WHERE variant_id IS NOT NULL;
+
+-- Order items keep the sku and label, so deleting a variant only unlinks it.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id BIGINT REFERENCES product_variants (id) ON DELETE SET NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku TEXT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_name TEXT;
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items (variant_id);
+
+ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id BIGINT REFERENCES product_variants (id) ON DELETE CASCADE;
@@ -0,0 +1,20 @@
+-- Gallery images of a product. Files live in media storage under key and
+-- thumbnail_key; anything stored there without a row here is an orphan.
+CREATE TABLE IF NOT EXISTS product_images (
+    id             BIGSERIAL PRIMARY KEY,
+    product_id     BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    key            TEXT NOT NULL,
+    thumbnail_key  TEXT NOT NULL,
+    url            TEXT NOT NULL,
+    thumbnail_url  TEXT NOT NULL,
+    content_type   TEXT,
+    size           BIGINT NOT NULL DEFAULT 0,
+    width          BIGINT NOT NULL DEFAULT 0,
+    height         BIGINT NOT NULL DEFAULT 0,
+    position       BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_key ON product_images (key);
+CREATE INDEX IF NOT EXISTS idx_product_images_thumbnail_key ON product_images (thumbnail_key);
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images (product_id, position);
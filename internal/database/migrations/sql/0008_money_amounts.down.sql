@@ -0,0 +1,33 @@
+CREATE OR REPLACE FUNCTION pg_temp.currency_exponent(currency TEXT) RETURNS INT AS $$
+    SELECT CASE
+        WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF',
+                          'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 0
+        WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 3
+        ELSE 2
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION pg_temp.major_units(amount BIGINT, currency TEXT) RETURNS NUMERIC AS $$
+    SELECT amount::NUMERIC / power(10::NUMERIC, pg_temp.currency_exponent(currency))
+$$ LANGUAGE sql IMMUTABLE;
+
+DROP TABLE IF EXISTS ledger_entries;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price NUMERIC;
+UPDATE order_items SET price = pg_temp.major_units(price_amount, price_currency);
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_amount;
+
+ALTER TABLE orders ALTER COLUMN currency DROP NOT NULL;
+ALTER TABLE orders ALTER COLUMN amount DROP NOT NULL;
+ALTER TABLE orders ALTER COLUMN amount TYPE NUMERIC USING pg_temp.major_units(amount, currency);
+
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS price NUMERIC;
+UPDATE product_variants v SET price = pg_temp.major_units(v.price_amount, p.price_currency)
+    FROM products p WHERE p.id = v.product_id AND v.price_amount IS NOT NULL;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS price_amount;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price NUMERIC;
+UPDATE products SET price = pg_temp.major_units(price_amount, price_currency);
+ALTER TABLE products DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE products DROP COLUMN IF EXISTS price_amount;
@@ -0,0 +1,74 @@
+-- Prices and amounts move from NUMERIC to integer minor units (cents for
+-- EUR) next to an ISO 4217 currency. The conversion is exact: a value with
+-- more decimals than its currency has aborts the migration instead of being
+-- rounded, and the down migration restores the same NUMERIC values.
+CREATE OR REPLACE FUNCTION pg_temp.currency_exponent(currency TEXT) RETURNS INT AS $$
+    SELECT CASE
+        WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF',
+                          'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 0
+        WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 3
+        ELSE 2
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION pg_temp.minor_units(value NUMERIC, currency TEXT) RETURNS BIGINT AS $$
+DECLARE
+    units NUMERIC := value * power(10::NUMERIC, pg_temp.currency_exponent(currency));
+BEGIN
+    IF units <> trunc(units) THEN
+        RAISE EXCEPTION 'amount % has more decimals than % allows', value, currency;
+    END IF;
+    RETURN units::BIGINT;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Orders were charged in payments.currency, so the latest order tells which
+-- currency existing prices are in. A store without orders is assumed to be
+-- on the default, EUR.
+CREATE TEMPORARY TABLE store_currency ON COMMIT DROP AS
+    SELECT coalesce((SELECT currency FROM orders WHERE currency IS NOT NULL ORDER BY id DESC LIMIT 1), 'EUR') AS code;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_currency TEXT;
+UPDATE products SET price_currency = (SELECT code FROM store_currency);
+UPDATE products SET price_amount = pg_temp.minor_units(coalesce(price, 0), price_currency);
+ALTER TABLE products ALTER COLUMN price_currency SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price;
+
+-- Variant prices are overrides in the currency of their product.
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS price_amount BIGINT;
+UPDATE product_variants v SET price_amount = pg_temp.minor_units(v.price, p.price_currency)
+    FROM products p WHERE p.id = v.product_id AND v.price IS NOT NULL;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS price;
+
+UPDATE orders SET currency = (SELECT code FROM store_currency) WHERE currency IS NULL;
+ALTER TABLE orders ALTER COLUMN amount TYPE BIGINT USING pg_temp.minor_units(coalesce(amount, 0), currency);
+ALTER TABLE orders ALTER COLUMN amount SET NOT NULL;
+ALTER TABLE orders ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_currency TEXT;
+UPDATE order_items i SET price_currency = o.currency, price_amount = pg_temp.minor_units(coalesce(i.price, 0), o.currency)
+    FROM orders o WHERE o.id = i.order_id;
+ALTER TABLE order_items ALTER COLUMN price_currency SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price;
+
+-- What each seller is owed. Paid orders so far are credited once.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id          BIGSERIAL PRIMARY KEY,
+    seller_id   BIGINT NOT NULL REFERENCES users (id),
+    order_id    BIGINT NOT NULL REFERENCES orders (id),
+    type        TEXT NOT NULL,
+    amount      BIGINT NOT NULL,
+    currency    TEXT NOT NULL,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_order_seller ON ledger_entries (order_id, seller_id, type);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_seller_id ON ledger_entries (seller_id, id);
+
+INSERT INTO ledger_entries (seller_id, order_id, type, amount, currency, created_at)
+    SELECT i.seller_id, o.id, 'sale', sum(i.price_amount * i.qty), o.currency, coalesce(o.paid_at, o.updated_at)
+    FROM orders o JOIN order_items i ON i.order_id = o.id
+    WHERE o.status = 'paid'
+    GROUP BY i.seller_id, o.id, o.currency, o.paid_at, o.updated_at;
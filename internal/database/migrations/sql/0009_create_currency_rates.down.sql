@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE orders DROP COLUMN IF EXISTS base_currency;
+
+DROP TABLE IF EXISTS currency_rates;
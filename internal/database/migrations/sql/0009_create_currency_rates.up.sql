@@ -0,0 +1,12 @@
+-- Units of each currency one unit of the store currency buys.
+CREATE TABLE IF NOT EXISTS currency_rates (
+    currency    TEXT PRIMARY KEY,
+    rate        NUMERIC NOT NULL CHECK (rate > 0),
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+-- Orders keep the rate they were converted at. Orders so far were charged
+-- in the store currency.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS base_currency TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC NOT NULL DEFAULT 1;
+UPDATE orders SET base_currency = currency WHERE base_currency IS NULL;
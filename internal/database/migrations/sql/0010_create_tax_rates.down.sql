@@ -0,0 +1,15 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_currency;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_rate;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_class;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_region;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_country;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_inclusive;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_amount;
+
+ALTER TABLE addresses DROP COLUMN IF EXISTS region;
+ALTER TABLE categories DROP COLUMN IF EXISTS tax_class;
+
+DROP TABLE IF EXISTS tax_rates;
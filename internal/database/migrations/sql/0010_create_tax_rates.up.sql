@@ -0,0 +1,31 @@
+-- Local tax table. A rate with an empty region covers the whole country;
+-- regional rates override it.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id          BIGSERIAL PRIMARY KEY,
+    country     TEXT NOT NULL,
+    region      TEXT NOT NULL DEFAULT '',
+    tax_class   TEXT NOT NULL DEFAULT 'standard',
+    name        TEXT,
+    rate        NUMERIC NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_rates_destination ON tax_rates (country, region, tax_class);
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tax_class TEXT NOT NULL DEFAULT 'standard';
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+
+-- Orders so far were charged without tax.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_currency TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_country TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_region TEXT;
+UPDATE orders SET tax_currency = currency WHERE tax_currency IS NULL;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_class TEXT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_rate NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_currency TEXT;
+UPDATE order_items SET tax_currency = price_currency WHERE tax_currency IS NULL;
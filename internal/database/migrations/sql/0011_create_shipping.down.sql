@@ -0,0 +1,12 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_method;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_method_id;
+
+DROP TABLE IF EXISTS shipping_methods;
+DROP TABLE IF EXISTS shipping_zones;
+
+ALTER TABLE products DROP COLUMN IF EXISTS height;
+ALTER TABLE products DROP COLUMN IF EXISTS width;
+ALTER TABLE products DROP COLUMN IF EXISTS length;
+ALTER TABLE products DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,37 @@
+-- Product weight in grams and packed dimensions in millimetres.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS weight BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS length BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS width BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS height BIGINT NOT NULL DEFAULT 0;
+
+-- Areas is a JSON list of countries and postcode ranges.
+CREATE TABLE IF NOT EXISTS shipping_zones (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    areas       TEXT,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS shipping_methods (
+    id              BIGSERIAL PRIMARY KEY,
+    zone_id         BIGINT NOT NULL REFERENCES shipping_zones (id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    rate            TEXT NOT NULL DEFAULT 'flat',
+    price_amount    BIGINT NOT NULL DEFAULT 0 CHECK (price_amount >= 0),
+    price_currency  TEXT NOT NULL,
+    price_per_kg    BIGINT NOT NULL DEFAULT 0 CHECK (price_per_kg >= 0),
+    free_over       BIGINT,
+    active          BOOLEAN DEFAULT true,
+    created_at      TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at      TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipping_methods_zone_id ON shipping_methods (zone_id);
+
+-- Orders so far shipped for free.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_method_id BIGINT REFERENCES shipping_methods (id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_method TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_currency TEXT;
+UPDATE orders SET shipping_currency = currency WHERE shipping_currency IS NULL;
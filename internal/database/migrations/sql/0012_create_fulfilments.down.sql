@@ -0,0 +1,6 @@
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_shipped_qty_check;
+ALTER TABLE order_items DROP COLUMN IF EXISTS shipped_qty;
+
+DROP TABLE IF EXISTS shipment_items;
+DROP TABLE IF EXISTS shipments;
+DROP TABLE IF EXISTS fulfilments;
@@ -0,0 +1,43 @@
+-- Each seller of an order fulfils their own items.
+CREATE TABLE IF NOT EXISTS fulfilments (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    seller_id   BIGINT NOT NULL REFERENCES users (id),
+    status      TEXT DEFAULT 'unfulfilled',
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fulfilments_order_seller ON fulfilments (order_id, seller_id);
+CREATE INDEX IF NOT EXISTS idx_fulfilments_seller_id ON fulfilments (seller_id);
+
+INSERT INTO fulfilments (order_id, seller_id)
+SELECT DISTINCT order_id, seller_id FROM order_items
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id               BIGSERIAL PRIMARY KEY,
+    fulfilment_id    BIGINT NOT NULL REFERENCES fulfilments (id) ON DELETE CASCADE,
+    order_id         BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    carrier          TEXT NOT NULL,
+    tracking_number  TEXT NOT NULL,
+    tracking_url     TEXT,
+    created_at       TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_fulfilment_id ON shipments (fulfilment_id);
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments (order_id);
+
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id             BIGSERIAL PRIMARY KEY,
+    shipment_id    BIGINT NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    order_item_id  BIGINT NOT NULL REFERENCES order_items (id) ON DELETE CASCADE,
+    qty            BIGINT NOT NULL CHECK (qty > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_items_shipment_id ON shipment_items (shipment_id);
+CREATE INDEX IF NOT EXISTS idx_shipment_items_order_item_id ON shipment_items (order_item_id);
+
+-- The check backs up the conditional update that ships units.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS shipped_qty BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD CONSTRAINT order_items_shipped_qty_check CHECK (shipped_qty >= 0 AND shipped_qty <= qty);
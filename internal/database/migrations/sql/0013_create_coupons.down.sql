@@ -0,0 +1,11 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_currency;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS cart_coupons;
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
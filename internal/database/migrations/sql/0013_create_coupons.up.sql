@@ -0,0 +1,58 @@
+-- Codes are stored upper case. The id lists are JSON; an empty list doesn't
+-- restrict the coupon. Deleted coupons keep their row, and their redemptions,
+-- with deleted_at set; only live codes have to be unique.
+CREATE TABLE IF NOT EXISTS coupons (
+    id                     BIGSERIAL PRIMARY KEY,
+    code                   TEXT NOT NULL,
+    type                   TEXT NOT NULL,
+    percent                BIGINT NOT NULL DEFAULT 0 CHECK (percent >= 0 AND percent <= 100),
+    amount_amount          BIGINT NOT NULL DEFAULT 0 CHECK (amount_amount >= 0),
+    amount_currency        TEXT,
+    min_subtotal_amount    BIGINT NOT NULL DEFAULT 0 CHECK (min_subtotal_amount >= 0),
+    min_subtotal_currency  TEXT,
+    category_ids           TEXT,
+    seller_ids             TEXT,
+    product_ids            TEXT,
+    usage_limit            BIGINT,
+    per_user_limit         BIGINT,
+    used                   BIGINT NOT NULL DEFAULT 0,
+    starts_at              TIMESTAMPTZ,
+    ends_at                TIMESTAMPTZ,
+    active                 BOOLEAN DEFAULT true,
+    created_at             TIMESTAMPTZ DEFAULT current_timestamp,
+    updated_at             TIMESTAMPTZ DEFAULT current_timestamp,
+    deleted_at             TIMESTAMPTZ,
+    CONSTRAINT coupons_used_check CHECK (used >= 0 AND (usage_limit IS NULL OR used <= usage_limit))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (code) WHERE deleted_at IS NULL;
+
+-- One row per order holding a coupon; it is deleted when the order is
+-- cancelled or expires.
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id          BIGSERIAL PRIMARY KEY,
+    coupon_id   BIGINT NOT NULL REFERENCES coupons (id) ON DELETE RESTRICT,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    order_id    BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupon_redemptions_order_id ON coupon_redemptions (order_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_user ON coupon_redemptions (coupon_id, user_id);
+
+CREATE TABLE IF NOT EXISTS cart_coupons (
+    user_id     BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    coupon_id   BIGINT NOT NULL REFERENCES coupons (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ DEFAULT current_timestamp
+);
+
+-- Orders so far had no discount.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_id BIGINT REFERENCES coupons (id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_code TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_currency TEXT;
+UPDATE orders SET discount_currency = currency WHERE discount_currency IS NULL;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_currency TEXT;
+UPDATE order_items SET discount_currency = price_currency WHERE discount_currency IS NULL;
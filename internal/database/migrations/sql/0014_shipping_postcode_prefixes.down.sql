@@ -0,0 +1,12 @@
+-- Only the digits of postcode prefixes survive the way back.
+UPDATE shipping_zones z SET areas = (
+    SELECT jsonb_agg(
+        CASE WHEN a ? 'postcode_from' OR a ? 'postcode_to' THEN
+            a || jsonb_build_object(
+                'postcode_from', NULLIF(regexp_replace(a->>'postcode_from', '\D', '', 'g'), '')::BIGINT,
+                'postcode_to', NULLIF(regexp_replace(a->>'postcode_to', '\D', '', 'g'), '')::BIGINT)
+        ELSE a END
+        ORDER BY i)
+    FROM jsonb_array_elements(z.areas::jsonb) WITH ORDINALITY AS e(a, i)
+)::TEXT
+WHERE z.areas LIKE '%postcode_%';
@@ -0,0 +1,30 @@
+-- Zone areas keep their ranges as compact postcode prefixes instead of
+-- numbers. Numbers stored so far get back the leading zeros of countries
+-- with fixed length numeric postcodes; a range without a start started at 0.
+CREATE OR REPLACE FUNCTION pg_temp.postcode_length(country TEXT) RETURNS INT AS $$
+    SELECT CASE
+        WHEN country IN ('PT', 'JP') THEN 7
+        WHEN country = 'BR' THEN 8
+        WHEN country IN ('DE', 'ES', 'FR', 'IT', 'PL', 'US') THEN 5
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- postcode_compact pads a number stored for country to its full length.
+CREATE OR REPLACE FUNCTION pg_temp.postcode_compact(country TEXT, postcode TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN length(postcode) < pg_temp.postcode_length(country) THEN lpad(postcode, pg_temp.postcode_length(country), '0')
+        ELSE postcode
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE shipping_zones z SET areas = (
+    SELECT jsonb_agg(
+        CASE WHEN a ? 'postcode_from' OR a ? 'postcode_to' THEN
+            a || jsonb_build_object(
+                'postcode_from', pg_temp.postcode_compact(a->>'country', COALESCE(a->>'postcode_from', '0')),
+                'postcode_to', pg_temp.postcode_compact(a->>'country', a->>'postcode_to'))
+        ELSE a END
+        ORDER BY i)
+    FROM jsonb_array_elements(z.areas::jsonb) WITH ORDINALITY AS e(a, i)
+)::TEXT
+WHERE z.areas LIKE '%postcode_%';
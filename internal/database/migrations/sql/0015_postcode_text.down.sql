@@ -0,0 +1,3 @@
+-- Only the digits of postcodes survive the way back.
+ALTER TABLE addresses ALTER COLUMN post_code TYPE BIGINT
+    USING NULLIF(regexp_replace(post_code, '\D', '', 'g'), '')::BIGINT;
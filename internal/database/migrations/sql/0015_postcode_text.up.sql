@@ -0,0 +1,33 @@
+-- Address postcodes become text written the way their country writes them,
+-- so they keep leading zeros and can hold letters. Numbers stored so far get
+-- back the leading zeros of countries with fixed length numeric postcodes.
+CREATE OR REPLACE FUNCTION pg_temp.postcode_length(country TEXT) RETURNS INT AS $$
+    SELECT CASE
+        WHEN country IN ('PT', 'JP') THEN 7
+        WHEN country = 'BR' THEN 8
+        WHEN country IN ('DE', 'ES', 'FR', 'IT', 'PL', 'US') THEN 5
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- postcode_compact pads a number stored for country to its full length.
+CREATE OR REPLACE FUNCTION pg_temp.postcode_compact(country TEXT, postcode TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN length(postcode) < pg_temp.postcode_length(country) THEN lpad(postcode, pg_temp.postcode_length(country), '0')
+        ELSE postcode
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION pg_temp.postcode_format(country TEXT, postcode TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN country = 'PT' THEN regexp_replace(postcode, '^(\d{4})(\d{3})$', '\1-\2')
+        WHEN country = 'JP' THEN regexp_replace(postcode, '^(\d{3})(\d{4})$', '\1-\2')
+        WHEN country = 'BR' THEN regexp_replace(postcode, '^(\d{5})(\d{3})$', '\1-\2')
+        WHEN country = 'PL' THEN regexp_replace(postcode, '^(\d{2})(\d{3})$', '\1-\2')
+        WHEN country = 'US' THEN regexp_replace(postcode, '^(\d{5})(\d{4})$', '\1-\2')
+        ELSE postcode
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE addresses ALTER COLUMN post_code TYPE TEXT USING post_code::TEXT;
+UPDATE addresses SET post_code = pg_temp.postcode_format(country, pg_temp.postcode_compact(country, post_code))
+WHERE post_code IS NOT NULL;
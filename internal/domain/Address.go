@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Address is an entry of a user's address book. PostCode is written the way
+// its country writes it.
+type Address struct {
+	ID              uint      `json:"id" gorm:"PrimaryKey"`
+	UserID          uint      `json:"user_id" gorm:"index;not null"`
+	AddressLine1    string    `json:"address_line1"`
+	AddressLine2    string    `json:"address_line2"`
+	City            string    `json:"city"`
+	PostCode        string    `json:"post_code"`
+	Country         string    `json:"country"`
+	Region          string    `json:"region" gorm:"not null;default:''"`
+	DefaultShipping bool      `json:"default_shipping" gorm:"default:false"`
+	DefaultBilling  bool      `json:"default_billing" gorm:"default:false"`
+	CreatedAt       time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// DefaultShippingAddress picks the address orders are delivered to: the
+// default shipping one, or the first if none is marked.
+func DefaultShippingAddress(addresses []Address) (Address, bool) {
+	for _, a := range addresses {
+		if a.DefaultShipping {
+			return a, true
+		}
+	}
+	if len(addresses) > 0 {
+		return addresses[0], true
+	}
+	return Address{}, false
+}
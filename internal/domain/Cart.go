@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+// CartItem is a product, or one variant of it, in a user's cart. The price
+// is not stored: the cart always shows the current price, which is fixed on
+// the order at checkout.
+type CartItem struct {
+	ID        uint            `json:"id" gorm:"PrimaryKey"`
+	UserID    uint            `json:"user_id" gorm:"index;not null"`
+	ProductID uint            `json:"product_id" gorm:"index;not null"`
+	VariantID *uint           `json:"variant_id" gorm:"index"`
+	Qty       uint            `json:"qty" gorm:"not null"`
+	Product   Product         `json:"product" gorm:"foreignKey:ProductID"`
+	Variant   *ProductVariant `json:"variant" gorm:"foreignKey:VariantID"`
+	CreatedAt time.Time       `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// Price is the current unit price of the item. Product and Variant must be
+// loaded.
+func (c CartItem) Price() money.Money {
+	if c.Variant != nil {
+		return c.Variant.PriceFor(c.Product)
+	}
+	return c.Product.Price
+}
+
+// Available is the stock of the item not held by unpaid orders.
+func (c CartItem) Available() uint {
+	if c.Variant != nil {
+		return c.Variant.Available()
+	}
+	return c.Product.Available()
+}
@@ -9,6 +9,7 @@ type Category struct {
 	ImageUrl     string    `json:"image_url"`
 	Products     []Product `json:"products"`
 	DisplayOrder int       `json:"display_order"`
+	TaxClass     string    `json:"tax_class" gorm:"not null;default:standard"`
 	CreatedAt    time.Time `json:"created_at" gorm:"default:current_timestamp"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"default:current_timestamp"`
 }
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// Coupon types. A percentage coupon takes Percent off the items it covers, a
+// fixed one takes Amount off them and a free shipping coupon waives the
+// shipping cost.
+const (
+	CouponPercentage   = "percentage"
+	CouponFixed        = "fixed"
+	CouponFreeShipping = "free_shipping"
+)
+
+// Coupon is a discount code. It can be used from StartsAt until EndsAt, by
+// at most UsageLimit orders in all and PerUserLimit orders of each user;
+// nil means no limit. Used counts the orders holding the coupon, which give
+// it back when they are cancelled or expire. Deleted coupons are kept, with
+// DeletedAt set, for the redemptions that count towards PerUserLimit.
+//
+// Carts worth less than MinSubtotal don't qualify. CategoryIDs, SellerIDs
+// and ProductIDs scope the coupon: it covers the items that are in every
+// list that is set. Amount and MinSubtotal are in their own currency and
+// must be converted to the cart's before calling Discount.
+type Coupon struct {
+	ID           uint        `json:"id" gorm:"PrimaryKey"`
+	Code         string      `json:"code" gorm:"not null"`
+	Type         string      `json:"type" gorm:"not null"`
+	Percent      uint        `json:"percent" gorm:"not null;default:0"`
+	Amount       money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	MinSubtotal  money.Money `json:"min_subtotal" gorm:"embedded;embeddedPrefix:min_subtotal_"`
+	CategoryIDs  []uint      `json:"category_ids" gorm:"serializer:json"`
+	SellerIDs    []uint      `json:"seller_ids" gorm:"serializer:json"`
+	ProductIDs   []uint      `json:"product_ids" gorm:"serializer:json"`
+	UsageLimit   *uint       `json:"usage_limit"`
+	PerUserLimit *uint       `json:"per_user_limit"`
+	Used         uint        `json:"used" gorm:"not null;default:0"`
+	StartsAt     *time.Time  `json:"starts_at"`
+	EndsAt       *time.Time  `json:"ends_at"`
+	Active       bool        `json:"active" gorm:"default:true"`
+	CreatedAt    time.Time   `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt    time.Time   `json:"updated_at" gorm:"default:current_timestamp"`
+	DeletedAt    *time.Time  `json:"-"`
+}
+
+// CouponRedemption is the use of a coupon by an order.
+type CouponRedemption struct {
+	ID        uint      `json:"id" gorm:"PrimaryKey"`
+	CouponID  uint      `json:"coupon_id" gorm:"index;not null"`
+	UserID    uint      `json:"user_id" gorm:"index;not null"`
+	OrderID   uint      `json:"order_id" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// CartCoupon is the coupon applied to a user's cart, redeemed at checkout.
+type CartCoupon struct {
+	UserID    uint      `json:"user_id" gorm:"PrimaryKey;autoIncrement:false"`
+	CouponID  uint      `json:"coupon_id" gorm:"not null"`
+	Coupon    Coupon    `json:"coupon" gorm:"foreignKey:CouponID"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// DiscountLine is a cart line as a coupon sees it; Amount is its unit price
+// times quantity.
+type DiscountLine struct {
+	ProductID  uint
+	CategoryID uint
+	SellerID   uint
+	Amount     money.Money
+}
+
+// Discount is what a coupon takes off a cart: Items holds the share of each
+// line, in the order of the lines, and Shipping the part of the shipping
+// cost.
+type Discount struct {
+	Items    []money.Money
+	Shipping money.Money
+}
+
+func (d Discount) Total() money.Money {
+	return money.Sum(append(d.Items, d.Shipping)...)
+}
+
+// Usable tells why the coupon can't be used at now, if it can't. It leaves
+// out the per user limit, which needs the user's redemptions.
+func (c Coupon) Usable(now time.Time) error {
+	switch {
+	case !c.Active:
+		return ErrCouponInvalid
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		return ErrCouponNotStarted
+	case c.EndsAt != nil && !now.Before(*c.EndsAt):
+		return ErrCouponExpired
+	case c.UsageLimit != nil && c.Used >= *c.UsageLimit:
+		return ErrCouponUsedUp
+	}
+	return nil
+}
+
+// Covers tells whether the coupon discounts the line.
+func (c Coupon) Covers(line DiscountLine) bool {
+	return (len(c.ProductIDs) == 0 || slices.Contains(c.ProductIDs, line.ProductID)) &&
+		(len(c.CategoryIDs) == 0 || slices.Contains(c.CategoryIDs, line.CategoryID)) &&
+		(len(c.SellerIDs) == 0 || slices.Contains(c.SellerIDs, line.SellerID))
+}
+
+// Discount works out what the coupon takes off lines shipped for shipping.
+// Lines, shipping, Amount and MinSubtotal must share a currency. The
+// discount on the items is split across the lines it covers in proportion
+// to their amounts, so every seller bears the part of it on their items.
+func (c Coupon) Discount(lines []DiscountLine, shipping money.Money) (Discount, error) {
+	var subtotal, covered money.Money
+	var err error
+	weights := make([]int64, len(lines))
+	for i, line := range lines {
+		if subtotal, err = subtotal.TryAdd(line.Amount); err != nil {
+			return Discount{}, err
+		}
+		if c.Covers(line) {
+			covered = covered.Add(line.Amount)
+			weights[i] = line.Amount.Amount
+		}
+	}
+
+	if covered.IsZero() {
+		return Discount{}, ErrCouponNotEligible
+	}
+
+	if subtotal.Amount < c.MinSubtotal.Amount {
+		return Discount{}, ErrCouponMinimum
+	}
+
+	off := money.New(0, subtotal.Currency)
+	d := Discount{Shipping: money.New(0, subtotal.Currency)}
+	switch c.Type {
+	case CouponPercentage:
+		off = covered.MulRat(big.NewRat(int64(c.Percent), 100))
+	case CouponFixed:
+		if off, err = off.TryAdd(c.Amount); err != nil {
+			return Discount{}, err
+		}
+		if off.Amount > covered.Amount {
+			off = covered
+		}
+	case CouponFreeShipping:
+		if d.Shipping, err = d.Shipping.TryAdd(shipping); err != nil {
+			return Discount{}, err
+		}
+	}
+
+	for _, share := range allocate(off.Amount, weights) {
+		d.Items = append(d.Items, money.New(share, subtotal.Currency))
+	}
+
+	return d, nil
+}
+
+// allocate splits amount in proportion to weights. The units lost to
+// rounding down go to the largest remainders, so the shares add up to
+// amount.
+func allocate(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	remainders := make([]*big.Int, len(weights))
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return shares
+	}
+
+	left := amount
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(amount), big.NewInt(w)), big.NewInt(total), new(big.Int))
+		shares[i] = q.Int64()
+		remainders[i] = r
+		left -= shares[i]
+	}
+
+	for ; left > 0; left-- {
+		largest := -1
+		for i, r := range remainders {
+			if weights[i] > 0 && (largest < 0 || r.Cmp(remainders[largest]) > 0) {
+				largest = i
+			}
+		}
+		shares[largest]++
+		remainders[largest] = big.NewInt(-1)
+	}
+
+	return shares
+}
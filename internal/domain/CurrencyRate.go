@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+// CurrencyRate is how many units of Currency one unit of the store currency
+// (payments.currency) buys. Prices can be shown and charged in every
+// currency that has a rate.
+type CurrencyRate struct {
+	Currency  string     `json:"currency" gorm:"PrimaryKey"`
+	Rate      money.Rate `json:"rate" gorm:"type:numeric;not null"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"default:current_timestamp"`
+}
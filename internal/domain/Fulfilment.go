@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Fulfilment statuses. Every seller of an order fulfils their own items; the
+// fulfilment is shipped once all of them are in a shipment.
+const (
+	FulfilmentUnfulfilled      = "unfulfilled"
+	FulfilmentPartiallyShipped = "partially_shipped"
+	FulfilmentShipped          = "shipped"
+)
+
+// Fulfilment is the part of an order a seller ships: a seller sub-order
+// covering the order items with its SellerID.
+type Fulfilment struct {
+	ID        uint       `json:"id" gorm:"PrimaryKey"`
+	OrderID   uint       `json:"order_id" gorm:"index;not null"`
+	SellerID  uint       `json:"seller_id" gorm:"index;not null"`
+	Status    string     `json:"status" gorm:"default:unfulfilled"`
+	Shipments []Shipment `json:"shipments" gorm:"foreignKey:FulfilmentID"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// Shipment is a parcel a seller sent, with some or all of the units of
+// their order items.
+type Shipment struct {
+	ID             uint           `json:"id" gorm:"PrimaryKey"`
+	FulfilmentID   uint           `json:"fulfilment_id" gorm:"index;not null"`
+	OrderID        uint           `json:"order_id" gorm:"index;not null"`
+	Carrier        string         `json:"carrier" gorm:"not null"`
+	TrackingNumber string         `json:"tracking_number" gorm:"not null"`
+	TrackingUrl    string         `json:"tracking_url"`
+	Items          []ShipmentItem `json:"items" gorm:"foreignKey:ShipmentID"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// ShipmentItem is Qty units of an order item in a shipment.
+type ShipmentItem struct {
+	ID          uint `json:"id" gorm:"PrimaryKey"`
+	ShipmentID  uint `json:"shipment_id" gorm:"index;not null"`
+	OrderItemID uint `json:"order_item_id" gorm:"index;not null"`
+	Qty         uint `json:"qty" gorm:"not null"`
+}
+
+// FulfilmentStatus is the status of a seller's part of an order given its
+// items, whose ShippedQty must be up to date.
+func FulfilmentStatus(items []OrderItem) string {
+	shipped, complete := false, true
+	for _, item := range items {
+		if item.ShippedQty > 0 {
+			shipped = true
+		}
+		if item.ShippedQty < item.Qty {
+			complete = false
+		}
+	}
+
+	switch {
+	case complete:
+		return FulfilmentShipped
+	case shipped:
+		return FulfilmentPartiallyShipped
+	default:
+		return FulfilmentUnfulfilled
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+// Ledger entry types. A sale credits the seller with their part of a paid
+// order.
+const (
+	LedgerSale = "sale"
+)
+
+// LedgerEntry records money owed to a seller. Amounts are positive for
+// credits and negative for debits; a seller's balance is their sum per
+// currency.
+type LedgerEntry struct {
+	ID        uint        `json:"id" gorm:"PrimaryKey"`
+	SellerID  uint        `json:"seller_id" gorm:"index;not null"`
+	OrderID   uint        `json:"order_id" gorm:"index;not null"`
+	Type      string      `json:"type" gorm:"not null"`
+	Amount    money.Money `json:"amount" gorm:"embedded"`
+	CreatedAt time.Time   `json:"created_at" gorm:"default:current_timestamp"`
+}
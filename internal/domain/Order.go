@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"go-ecommerce-app/pkg/tax"
+	"time"
+)
+
+// Order statuses. An order is created pending payment with its stock
+// reserved; it becomes paid when the payment succeeds, or expired/cancelled
+// (releasing the stock) when it doesn't. RefundPending marks orders paid
+// after expiring whose stock was sold in the meantime.
+const (
+	OrderPendingPayment = "pending_payment"
+	OrderPaid           = "paid"
+	OrderCancelled      = "cancelled"
+	OrderExpired        = "expired"
+	OrderRefundPending  = "refund_pending"
+)
+
+// Reservation statuses. An active reservation holds stock; it is committed
+// (the stock is taken) when the order is paid and released otherwise.
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+// Order is charged in the currency of Amount. ExchangeRate is the rate from
+// BaseCurrency, the store currency, to it at checkout; item prices were
+// converted at that rate, so later rate changes don't move the totals.
+//
+// Tax is the sum of the items' tax, worked out for TaxCountry and TaxRegion.
+// With TaxInclusive item prices already contain it; otherwise it was added
+// to Amount. Shipping is the cost of the method chosen at checkout, also
+// part of Amount; ShippingMethod keeps its name should it be deleted.
+//
+// Discount is what the coupon with CouponCode took off Amount: the items'
+// Discount plus whatever it waived of Shipping.
+type Order struct {
+	ID               uint         `json:"id" gorm:"PrimaryKey"`
+	UserID           uint         `json:"user_id" gorm:"index;not null"`
+	Reference        string       `json:"reference" gorm:"uniqueIndex;not null"`
+	Status           string       `json:"status" gorm:"index;default:pending_payment"`
+	Amount           money.Money  `json:"amount" gorm:"embedded"`
+	BaseCurrency     string       `json:"base_currency"`
+	ExchangeRate     money.Rate   `json:"exchange_rate" gorm:"type:numeric"`
+	Tax              money.Money  `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	TaxInclusive     bool         `json:"tax_inclusive"`
+	TaxCountry       string       `json:"tax_country"`
+	TaxRegion        string       `json:"tax_region"`
+	ShippingMethodID *uint        `json:"shipping_method_id"`
+	ShippingMethod   string       `json:"shipping_method"`
+	Shipping         money.Money  `json:"shipping" gorm:"embedded;embeddedPrefix:shipping_"`
+	CouponID         *uint        `json:"coupon_id"`
+	CouponCode       string       `json:"coupon_code"`
+	Discount         money.Money  `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	PaymentID        string       `json:"payment_id" gorm:"index"`
+	ExpiresAt        time.Time    `json:"expires_at" gorm:"index"`
+	PaidAt           *time.Time   `json:"paid_at"`
+	Items            []OrderItem  `json:"items" gorm:"foreignKey:OrderID"`
+	Fulfilments      []Fulfilment `json:"fulfilments" gorm:"foreignKey:OrderID"`
+	CreatedAt        time.Time    `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time    `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// OrderItem copies the product as it was at checkout, so later edits to the
+// product don't change past orders. Discount is the coupon's share of the
+// whole line, Price times Qty, and Tax the tax on what is left of it at
+// TaxRate percent. ShippedQty counts the units the seller has put in
+// shipments.
+type OrderItem struct {
+	ID          uint        `json:"id" gorm:"PrimaryKey"`
+	OrderID     uint        `json:"order_id" gorm:"index;not null"`
+	ProductID   uint        `json:"product_id" gorm:"index;not null"`
+	VariantID   *uint       `json:"variant_id" gorm:"index"`
+	SellerID    uint        `json:"seller_id" gorm:"index;not null"`
+	Name        string      `json:"name"`
+	Sku         string      `json:"sku"`
+	VariantName string      `json:"variant_name"`
+	ImageUrl    string      `json:"image_url"`
+	Price       money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	Qty         uint        `json:"qty"`
+	TaxClass    string      `json:"tax_class"`
+	TaxRate     tax.Rate    `json:"tax_rate" gorm:"type:numeric"`
+	Tax         money.Money `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	Discount    money.Money `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	ShippedQty  uint        `json:"shipped_qty" gorm:"not null;default:0"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// ItemTotal is what the buyer pays for a line, after its discount and with
+// tax included.
+func (o Order) ItemTotal(item OrderItem) money.Money {
+	total := item.Price.Mul(int64(item.Qty)).Sub(item.Discount)
+	if !o.TaxInclusive {
+		total = total.Add(item.Tax)
+	}
+	return total
+}
+
+// Sellers lists the sellers of the order's items.
+func (o Order) Sellers() []uint {
+	var sellers []uint
+	seen := map[uint]bool{}
+	for _, item := range o.Items {
+		if !seen[item.SellerID] {
+			seen[item.SellerID] = true
+			sellers = append(sellers, item.SellerID)
+		}
+	}
+	return sellers
+}
+
+// ForSeller is the part of the order sold by sellerId: their items and
+// fulfilment, with Amount, Tax and Discount worked out from those items
+// alone. Shipping and the coupon belong to the whole order and are left out.
+func (o Order) ForSeller(sellerId uint) Order {
+	view := o
+	view.Amount = money.New(0, o.Amount.Currency)
+	view.Tax = money.New(0, o.Amount.Currency)
+	view.Discount = money.New(0, o.Amount.Currency)
+	view.Shipping = money.New(0, o.Amount.Currency)
+	view.CouponID = nil
+	view.CouponCode = ""
+
+	view.Items = nil
+	for _, item := range o.Items {
+		if item.SellerID == sellerId {
+			view.Items = append(view.Items, item)
+			view.Amount = view.Amount.Add(o.ItemTotal(item))
+			view.Tax = view.Tax.Add(item.Tax)
+			view.Discount = view.Discount.Add(item.Discount)
+		}
+	}
+
+	view.Fulfilments = nil
+	for _, f := range o.Fulfilments {
+		if f.SellerID == sellerId {
+			view.Fulfilments = append(view.Fulfilments, f)
+		}
+	}
+	return view
+}
+
+// StockReservation holds Qty units of a product, or of one of its variants,
+// for an unpaid order until ExpiresAt.
+type StockReservation struct {
+	ID        uint      `json:"id" gorm:"PrimaryKey"`
+	OrderID   uint      `json:"order_id" gorm:"index;not null"`
+	ProductID uint      `json:"product_id" gorm:"index;not null"`
+	VariantID *uint     `json:"variant_id" gorm:"index"`
+	Qty       uint      `json:"qty" gorm:"not null"`
+	Status    string    `json:"status" gorm:"index;default:active"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
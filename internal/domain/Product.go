@@ -1,16 +1,66 @@
 package domain
 
-import "time"
+import (
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
 
+// Product is sold by the seller UserId. Its Weight is in grams and Length,
+// Width and Height, of the packed product, in millimetres.
 type Product struct {
-	ID          uint      `json:"id" gorm:"PrimaryKey"`
-	Name        string    `json:"name" gorm:"index;"`
-	Description string    `json:"description"`
-	CategoryId  uint      `json:"category_id"`
-	ImageUrl    string    `json:"image_url"`
-	Price       float64   `json:"products"`
-	UserId      int       `json:"user_id"`
-	Stock       uint      `json:"stock"`
-	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+	ID          uint             `json:"id" gorm:"PrimaryKey"`
+	Name        string           `json:"name" gorm:"index;"`
+	Description string           `json:"description"`
+	CategoryId  uint             `json:"category_id"`
+	ImageUrl    string           `json:"image_url"`
+	Price       money.Money      `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	UserId      int              `json:"user_id"`
+	Stock       uint             `json:"stock"`
+	Reserved    uint             `json:"reserved" gorm:"not null;default:0"`
+	Weight      uint             `json:"weight" gorm:"not null;default:0"`
+	Length      uint             `json:"length" gorm:"not null;default:0"`
+	Width       uint             `json:"width" gorm:"not null;default:0"`
+	Height      uint             `json:"height" gorm:"not null;default:0"`
+	Options     []ProductOption  `json:"options" gorm:"foreignKey:ProductID"`
+	Variants    []ProductVariant `json:"variants" gorm:"foreignKey:ProductID"`
+	Images      []ProductImage   `json:"images" gorm:"foreignKey:ProductID"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// ShippingWeight is the weight a carrier charges one unit for, in grams:
+// the actual Weight or, for light but bulky goods, the volumetric weight of
+// the Length x Width x Height millimetre box at divisor cubic centimetres
+// per kilogram. A divisor of zero ignores the dimensions.
+func (p Product) ShippingWeight(divisor int) uint {
+	if divisor <= 0 {
+		return p.Weight
+	}
+
+	volumetric := uint64(p.Length) * uint64(p.Width) * uint64(p.Height) / uint64(divisor)
+	return max(p.Weight, uint(volumetric))
+}
+
+// HasVariants reports whether the product is sold through variants, in
+// which case its own stock is not used. Options must be loaded.
+func (p Product) HasVariants() bool {
+	return len(p.Options) > 0
+}
+
+// Variant returns the variant with the given id. Variants must be loaded.
+func (p Product) Variant(id uint) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// Available is the stock not held by unpaid orders.
+func (p Product) Available() uint {
+	if p.Reserved > p.Stock {
+		return 0
+	}
+	return p.Stock - p.Reserved
 }
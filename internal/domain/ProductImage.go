@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ProductImage is one picture in a product's gallery, ordered by Position.
+// Key and ThumbnailKey locate the files in media storage; the urls are
+// resolved when the image is uploaded.
+type ProductImage struct {
+	ID           uint      `json:"id" gorm:"PrimaryKey"`
+	ProductID    uint      `json:"product_id" gorm:"index;not null"`
+	Key          string    `json:"-" gorm:"uniqueIndex;not null"`
+	ThumbnailKey string    `json:"-" gorm:"not null"`
+	Url          string    `json:"url" gorm:"not null"`
+	ThumbnailUrl string    `json:"thumbnail_url" gorm:"not null"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"strings"
+	"time"
+)
+
+// Shipping rate types. A flat method costs its Price; a weight method adds
+// PricePerKg for every started kilogram.
+const (
+	ShippingFlat   = "flat"
+	ShippingWeight = "weight"
+)
+
+// ShippingZone is a set of destinations sharing shipping methods.
+type ShippingZone struct {
+	ID        uint             `json:"id" gorm:"PrimaryKey"`
+	Name      string           `json:"name" gorm:"not null"`
+	Areas     []ShippingArea   `json:"areas" gorm:"serializer:json"`
+	Methods   []ShippingMethod `json:"methods" gorm:"foreignKey:ZoneID"`
+	CreatedAt time.Time        `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time        `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// ShippingArea is a country (ISO 3166-1 alpha-2), or some of its postcodes.
+// Country "*" is anywhere else. PostcodeFrom and PostcodeTo are compact
+// postcode prefixes: the area holds the postcodes that start with one from
+// PostcodeFrom to PostcodeTo, in alphabetical order, e.g. "1000" to "1999"
+// for Lisbon or "SW" to "SW" for south west London. Without PostcodeTo the
+// area is the postcodes starting with PostcodeFrom.
+type ShippingArea struct {
+	Country      string `json:"country"`
+	PostcodeFrom string `json:"postcode_from,omitempty"`
+	PostcodeTo   string `json:"postcode_to,omitempty"`
+}
+
+// Covers reports whether postcode, in any format, is in the area's range.
+// It is true for any postcode when the area has no range.
+func (a ShippingArea) Covers(postcode string) bool {
+	to := a.PostcodeTo
+	if to == "" {
+		to = a.PostcodeFrom
+	}
+
+	p := CompactPostcode(postcode)
+	return prefix(p, len(a.PostcodeFrom)) >= a.PostcodeFrom && prefix(p, len(to)) <= to
+}
+
+// CompactPostcode is postcode in upper case without spaces or hyphens, as
+// ranges are compared, e.g. "SW1A1AA" for "sw1a 1aa".
+func CompactPostcode(postcode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(postcode)))
+}
+
+func prefix(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// ShippingMethod is a way of delivering to a zone. Prices are in minor
+// units of the currency of Price; with FreeOver set, orders worth at least
+// that much ship for free.
+type ShippingMethod struct {
+	ID         uint        `json:"id" gorm:"PrimaryKey"`
+	ZoneID     uint        `json:"zone_id" gorm:"index;not null"`
+	Name       string      `json:"name" gorm:"not null"`
+	Rate       string      `json:"rate" gorm:"not null;default:flat"`
+	Price      money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	PricePerKg int64       `json:"price_per_kg" gorm:"not null;default:0"`
+	FreeOver   *int64      `json:"free_over"`
+	Active     bool        `json:"active" gorm:"default:true"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt  time.Time   `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// ShippingQuote is what a method costs for a cart, in the currency the cart
+// is shown or charged in.
+type ShippingQuote struct {
+	Zone   ShippingZone
+	Method ShippingMethod
+	Cost   money.Money
+}
+
+// Match tells how well the zone covers a destination: 0 if it doesn't, and
+// higher for a postcode range than for the whole country, and for the
+// country than for "*".
+func (z ShippingZone) Match(country string, postcode string) int {
+	best := 0
+	for _, a := range z.Areas {
+		switch {
+		case a.Country == "*":
+			best = max(best, 1)
+		case a.Country != country:
+		case a.PostcodeFrom == "" && a.PostcodeTo == "":
+			best = max(best, 2)
+		case postcode != "" && a.Covers(postcode):
+			best = max(best, 3)
+		}
+	}
+	return best
+}
+
+// Cost is the price of shipping weight grams of goods worth subtotal, in the
+// method's currency.
+func (m ShippingMethod) Cost(weight uint, subtotal money.Money) money.Money {
+	if m.FreeOver != nil && subtotal.Amount >= *m.FreeOver {
+		return money.New(0, m.Price.Currency)
+	}
+
+	cost := m.Price
+	if m.Rate == ShippingWeight {
+		kg := (int64(weight) + 999) / 1000
+		cost = cost.Add(money.New(m.PricePerKg*kg, m.Price.Currency))
+	}
+	return cost
+}
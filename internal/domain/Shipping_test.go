@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+func TestShippingAreaCovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		area     ShippingArea
+		postcode string
+		want     bool
+	}{
+		{"inside range", ShippingArea{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"}, "1500-123", true},
+		{"range start", ShippingArea{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"}, "1000-001", true},
+		{"range end", ShippingArea{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"}, "1999-999", true},
+		{"after range", ShippingArea{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"}, "2000-001", false},
+		{"before range", ShippingArea{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"}, "0999-999", false},
+		{"open start padded", ShippingArea{Country: "DE", PostcodeFrom: "00000", PostcodeTo: "09999"}, "01067", true},
+		{"open start padded, outside", ShippingArea{Country: "DE", PostcodeFrom: "00000", PostcodeTo: "09999"}, "10115", false},
+		{"open start from zero", ShippingArea{Country: "PT", PostcodeFrom: "0", PostcodeTo: "1999"}, "1500-123", true},
+		{"prefix only", ShippingArea{Country: "GB", PostcodeFrom: "SW"}, "SW1A 1AA", true},
+		{"prefix only, lower case", ShippingArea{Country: "GB", PostcodeFrom: "SW"}, "sw1a 1aa", true},
+		{"prefix only, other area", ShippingArea{Country: "GB", PostcodeFrom: "SW"}, "SE1 7PB", false},
+		{"letter range", ShippingArea{Country: "GB", PostcodeFrom: "EC", PostcodeTo: "EH"}, "EH1 1YZ", true},
+		{"letter range, outside", ShippingArea{Country: "GB", PostcodeFrom: "EC", PostcodeTo: "EH"}, "E1 6AN", false},
+		{"no range", ShippingArea{Country: "PT"}, "1500-123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.area.Covers(tt.postcode); got != tt.want {
+				t.Errorf("%+v.Covers(%q) = %v, want %v", tt.area, tt.postcode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippingZoneMatch(t *testing.T) {
+	zone := ShippingZone{Areas: []ShippingArea{
+		{Country: "*"},
+		{Country: "ES"},
+		{Country: "PT", PostcodeFrom: "1000", PostcodeTo: "1999"},
+	}}
+
+	tests := []struct {
+		country, postcode string
+		want              int
+	}{
+		{"PT", "1500-123", 3},
+		{"PT", "4000-001", 1},
+		{"PT", "", 1},
+		{"ES", "28001", 2},
+		{"FR", "75001", 1},
+	}
+
+	for _, tt := range tests {
+		if got := zone.Match(tt.country, tt.postcode); got != tt.want {
+			t.Errorf("Match(%q, %q) = %d, want %d", tt.country, tt.postcode, got, tt.want)
+		}
+	}
+}
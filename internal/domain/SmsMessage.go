@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+const (
+	SmsReserved = "reserved"
+	SmsSent     = "sent"
+	SmsFailed   = "failed"
+	SmsRejected = "rejected"
+)
+
+// SmsMessage records every text message we tried to send. Reserved and sent
+// messages count towards the send caps; failed and rejected ones do not.
+type SmsMessage struct {
+	ID        uint      `json:"id" gorm:"PrimaryKey"`
+	Phone     string    `json:"phone" gorm:"index;not null"`
+	Purpose   string    `json:"purpose"`
+	Status    string    `json:"status" gorm:"default:reserved"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
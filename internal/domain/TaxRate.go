@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/tax"
+	"time"
+)
+
+// TaxRate is a row of the local tax table. Goods whose category has
+// TaxClass and that are delivered to Country are taxed at Rate percent; a
+// rate with a Region only applies there and overrides the country's.
+type TaxRate struct {
+	ID        uint      `json:"id" gorm:"PrimaryKey"`
+	Country   string    `json:"country" gorm:"not null"`
+	Region    string    `json:"region" gorm:"not null;default:''"`
+	TaxClass  string    `json:"tax_class" gorm:"not null;default:standard"`
+	Name      string    `json:"name"`
+	Rate      tax.Rate  `json:"rate" gorm:"type:numeric;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -5,6 +5,7 @@ import "time"
 const (
 	SELLER = "seller"
 	BUYER  = "buyer"
+	ADMIN  = "admin"
 )
 
 type User struct {
@@ -13,11 +14,12 @@ type User struct {
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email" gorm:"index;unique;not null"`
 	Phone     string    `json:"phone"`
-	Password  string    `json:"password"`
-	Code      int       `json:"code"`
-	Expiry    time.Time `json:"expiry"`
+	Password  string    `json:"-"`
+	Code      int       `json:"-"`
+	Expiry    time.Time `json:"-"`
 	Verified  bool      `json:"verified" gorm:"default:false"`
 	UserType  string    `json:"user_type" gorm:"default:buyer"`
+	Addresses []Address `json:"addresses"`
 	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
 }
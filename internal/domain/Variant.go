@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductOption is a dimension a product varies by, e.g. "size" with the
+// values S, M and L. A product with options is sold through its variants.
+type ProductOption struct {
+	ID        uint      `json:"id" gorm:"PrimaryKey"`
+	ProductID uint      `json:"product_id" gorm:"index;not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	Position  int       `json:"position"`
+	Values    []string  `json:"values" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// ProductVariant is one combination of option values, e.g. size M in red,
+// with its own SKU and stock. Price, in minor units of the product's
+// currency, overrides the product price when set.
+type ProductVariant struct {
+	ID         uint              `json:"id" gorm:"PrimaryKey"`
+	ProductID  uint              `json:"product_id" gorm:"index;not null"`
+	Sku        string            `json:"sku" gorm:"uniqueIndex;not null"`
+	Options    map[string]string `json:"options" gorm:"serializer:json"`
+	OptionsKey string            `json:"-" gorm:"not null"`
+	Price      *int64            `json:"price" gorm:"column:price_amount"`
+	Stock      uint              `json:"stock" gorm:"not null;default:0"`
+	Reserved   uint              `json:"reserved" gorm:"not null;default:0"`
+	ImageUrl   string            `json:"image_url"`
+	Active     bool              `json:"active" gorm:"default:true"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// Available is the stock not held by unpaid orders.
+func (v ProductVariant) Available() uint {
+	if v.Reserved > v.Stock {
+		return 0
+	}
+	return v.Stock - v.Reserved
+}
+
+// PriceFor is the price of the variant of product p.
+func (v ProductVariant) PriceFor(p Product) money.Money {
+	if v.Price != nil {
+		return money.Money{Amount: *v.Price, Currency: p.Price.Currency}
+	}
+	return p.Price
+}
+
+// Label names the variant by its option values in the product's option
+// order, e.g. "M / Red".
+func (v ProductVariant) Label(options []ProductOption) string {
+	values := make([]string, 0, len(options))
+	for _, o := range options {
+		if value, ok := v.Options[o.Name]; ok {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, " / ")
+}
+
+// VariantOptionsKey identifies a combination of option values regardless of
+// key order or case, so two variants of a product can't share one.
+func VariantOptionsKey(options map[string]string) string {
+	pairs := make([]string, 0, len(options))
+	for name, value := range options {
+		pairs = append(pairs, strings.ToLower(name)+"="+strings.ToLower(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
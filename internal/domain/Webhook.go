@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+const (
+	EventOrderCreated   = "order.created"
+	EventOrderPaid      = "order.paid"
+	EventOrderCancelled = "order.cancelled"
+	EventOrderShipped   = "order.shipped"
+	EventWebhookTest    = "webhook.test"
+)
+
+// WebhookEventTypes lists the events a seller can subscribe an endpoint to.
+var WebhookEventTypes = []string{
+	EventOrderCreated,
+	EventOrderPaid,
+	EventOrderCancelled,
+	EventOrderShipped,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookEndpoint struct {
+	ID          uint      `json:"id" gorm:"PrimaryKey"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Url         string    `json:"url" gorm:"not null"`
+	Description string    `json:"description"`
+	Secret      string    `json:"-" gorm:"not null"`
+	Events      []string  `json:"events" gorm:"serializer:json"`
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// Subscribes reports whether the endpoint should receive the given event.
+func (e WebhookEndpoint) Subscribes(event string) bool {
+	if event == EventWebhookTest {
+		return true
+	}
+
+	for _, ev := range e.Events {
+		if ev == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDelivery struct {
+	ID               uint             `json:"id" gorm:"PrimaryKey"`
+	EndpointID       uint             `json:"endpoint_id" gorm:"index;not null"`
+	EventType        string           `json:"event_type"`
+	Payload          string           `json:"payload"`
+	Status           string           `json:"status" gorm:"index;default:pending"`
+	Attempts         int              `json:"attempts"`
+	LastResponseCode int              `json:"last_response_code"`
+	LastError        string           `json:"last_error"`
+	NextAttemptAt    time.Time        `json:"next_attempt_at" gorm:"index"`
+	DeliveredAt      *time.Time       `json:"delivered_at"`
+	DeliveryAttempts []WebhookAttempt `json:"delivery_attempts,omitempty" gorm:"foreignKey:DeliveryID"`
+	CreatedAt        time.Time        `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time        `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+type WebhookAttempt struct {
+	ID           uint      `json:"id" gorm:"PrimaryKey"`
+	DeliveryID   uint      `json:"delivery_id" gorm:"index;not null"`
+	ResponseCode int       `json:"response_code"`
+	Error        string    `json:"error"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,150 @@
+package domain
+
+import "errors"
+
+// Error kinds. Every *Error unwraps to one of these so callers can branch on
+// the kind with errors.Is(err, domain.ErrNotFound) regardless of the code.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("too many requests")
+	ErrUnavailable  = errors.New("service unavailable")
+)
+
+// Error is a typed application error. Code is a stable machine-readable
+// identifier (e.g. "user_not_found") returned to API clients next to the
+// human readable Message.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// Wrap attaches the underlying cause to the error, for logging.
+func (e *Error) Wrap(err error) *Error {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+func NotFound(code string, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func Conflict(code string, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func Validation(code string, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+func Unauthorized(code string, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func Forbidden(code string, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func RateLimited(code string, message string) *Error {
+	return &Error{Kind: ErrRateLimited, Code: code, Message: message}
+}
+
+func Unavailable(code string, message string) *Error {
+	return &Error{Kind: ErrUnavailable, Code: code, Message: message}
+}
+
+var (
+	ErrUserNotFound       = NotFound("user_not_found", "user does not exist")
+	ErrEmailTaken         = Conflict("email_taken", "an account with this email already exists")
+	ErrAddressNotFound    = NotFound("address_not_found", "address does not exist")
+	ErrBankAccountTaken   = Conflict("bank_account_taken", "bank account is already registered")
+	ErrCategoryNotFound   = NotFound("category_not_found", "category does not exist")
+	ErrWebhookNotFound    = NotFound("webhook_not_found", "webhook endpoint does not exist")
+	ErrDeliveryNotFound   = NotFound("webhook_delivery_not_found", "webhook delivery does not exist")
+	ErrDeliveryBusy       = Conflict("webhook_delivery_busy", "webhook delivery is being sent or waiting for a retry, try again later")
+	ErrInvalidCredentials = Unauthorized("invalid_credentials", "invalid email or password")
+	ErrSellerRequired     = Forbidden("seller_required", "only sellers can access this resource")
+	ErrAdminRequired      = Forbidden("admin_required", "only admins can access this resource")
+	ErrAlreadySeller      = Conflict("already_seller", "you have already joined seller program")
+	ErrAlreadyVerified    = Conflict("already_verified", "user is already verified")
+	ErrInvalidCode        = Validation("invalid_code", "invalid verification code")
+	ErrCodeExpired        = Validation("code_expired", "verification code has expired")
+	ErrInvalidWebhookUrl  = Validation("invalid_webhook_url", "webhook url must be an absolute http(s) url on a public host")
+	ErrTooManyRequests    = RateLimited("rate_limited", "too many requests, try again later")
+	ErrInvalidPhone       = Validation("invalid_phone", "phone number is not valid")
+	ErrInvalidPostcode    = Validation("invalid_postcode", "postcode is not valid for the country")
+	ErrPhoneNotAllowed    = Validation("phone_country_not_allowed", "phone numbers from this country are not supported")
+	ErrPhoneBlocked       = Validation("phone_blocked", "this phone number cannot receive text messages")
+	ErrSmsLimitReached    = RateLimited("sms_limit_reached", "too many messages sent to this number, try again tomorrow")
+	ErrSmsUnavailable     = RateLimited("sms_unavailable", "text messages are temporarily unavailable, try again later")
+	ErrProductNotFound    = NotFound("product_not_found", "product does not exist")
+	ErrOrderNotFound      = NotFound("order_not_found", "order does not exist")
+	ErrCartEmpty          = Validation("cart_empty", "your cart is empty")
+	ErrOutOfStock         = Conflict("out_of_stock", "not enough stock for one or more items in your cart")
+	ErrOrderNotPending    = Conflict("order_not_pending", "only orders awaiting payment can be cancelled")
+	ErrPaymentsDisabled   = Unavailable("payments_disabled", "checkout is not available right now")
+	ErrPaymentFailed      = Unavailable("payment_failed", "could not start the payment, try again")
+	ErrInvalidSignature   = Unauthorized("invalid_signature", "invalid event signature")
+	ErrVariantNotFound    = NotFound("variant_not_found", "variant does not exist")
+	ErrVariantExists      = Conflict("variant_exists", "a variant with this sku or these options already exists")
+	ErrVariantReserved    = Conflict("variant_reserved", "unpaid orders hold stock of this variant")
+	ErrVariantRequired    = Validation("variant_required", "choose a variant of this product")
+	ErrVariantUnavailable = Conflict("variant_unavailable", "this variant is no longer sold")
+	ErrInvalidVariant     = Validation("invalid_variant_options", "variant options must set one listed value for each product option")
+	ErrNoProductOptions   = Validation("product_options_required", "set the product options before adding variants")
+	ErrOptionsInUse       = Conflict("product_options_in_use", "existing variants use options or values that would be removed")
+	ErrStockBelowReserved = Conflict("stock_below_reserved", "stock cannot be lower than what unpaid orders hold")
+	ErrCurrencyNotCharged = Conflict("currency_not_charged", "this product is priced in a currency we can't charge")
+	ErrUnknownCurrency    = Validation("currency_not_supported", "prices are not available in this currency")
+	ErrRateNotFound       = NotFound("currency_rate_not_found", "there is no rate for this currency")
+	ErrTaxRateNotFound    = NotFound("tax_rate_not_found", "tax rate does not exist")
+	ErrTaxRateExists      = Conflict("tax_rate_exists", "a tax rate for this country, region and class already exists")
+	ErrAddressRequired    = Validation("address_required", "add a shipping address before checking out")
+	ErrTaxUnavailable     = Unavailable("tax_unavailable", "could not calculate tax, try again")
+	ErrZoneNotFound       = NotFound("shipping_zone_not_found", "shipping zone does not exist")
+	ErrPostcodeRange      = Validation("invalid_postcode_range", "a postcode range must end at or after its start")
+	ErrMethodNotFound     = NotFound("shipping_method_not_found", "shipping method does not exist")
+	ErrNoShipping         = Conflict("shipping_unavailable", "we don't ship to your address")
+	ErrShippingRequired   = Validation("shipping_method_required", "choose a shipping method")
+	ErrInvalidShipping    = Validation("invalid_shipping_method", "this shipping method doesn't deliver to your address")
+	ErrFulfilmentNotFound = NotFound("fulfilment_not_found", "fulfilment does not exist")
+	ErrOrderNotPaid       = Conflict("order_not_paid", "only paid orders can be shipped")
+	ErrNothingToShip      = Conflict("nothing_to_ship", "every item of this order has already shipped")
+	ErrInvalidShipment    = Validation("invalid_shipment_items", "ship only your own items of this order, at most the units not shipped yet")
+	ErrCouponNotFound     = NotFound("coupon_not_found", "coupon does not exist")
+	ErrCouponExists       = Conflict("coupon_exists", "a coupon with this code already exists")
+	ErrCouponWindow       = Validation("invalid_coupon_window", "a coupon must end after it starts")
+	ErrCouponInvalid      = Validation("coupon_invalid", "this coupon code is not valid")
+	ErrCouponNotStarted   = Validation("coupon_not_started", "this coupon can't be used yet")
+	ErrCouponExpired      = Validation("coupon_expired", "this coupon has expired")
+	ErrCouponUsedUp       = Conflict("coupon_used_up", "this coupon has reached its usage limit")
+	ErrCouponUserLimit    = Conflict("coupon_user_limit", "you have already used this coupon as often as allowed")
+	ErrCouponMinimum      = Validation("coupon_minimum_not_met", "your cart is below the minimum value for this coupon")
+	ErrCouponNotEligible  = Validation("coupon_not_applicable", "this coupon doesn't apply to the items in your cart")
+	ErrCouponBelowUsed    = Conflict("coupon_limit_below_used", "the usage limit cannot be lower than the uses so far")
+	ErrImageNotFound      = NotFound("image_not_found", "image does not exist")
+	ErrImagesRequired     = Validation("images_required", "upload at least one image")
+	ErrUnsupportedImage   = Validation("unsupported_image", "images must be JPEG, PNG, GIF or WebP")
+	ErrInvalidImageOrder  = Validation("invalid_image_order", "list every image of the product exactly once")
+	ErrStorageUnavailable = Unavailable("storage_unavailable", "could not store the images, try again")
+)
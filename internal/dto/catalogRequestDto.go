@@ -0,0 +1,51 @@
+package dto
+
+type ProductOptionInput struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max=50,unique,dive,required,max=50"`
+}
+
+// ProductOptionsInput replaces the option types of a product; the order of
+// the list is the order options are shown in.
+type ProductOptionsInput struct {
+	Options []ProductOptionInput `json:"options" validate:"max=5,unique=Name,dive"`
+}
+
+// VariantInput creates one variant. Options maps every option name of the
+// product to one of its values; Price, in minor units of the product's
+// currency (e.g. cents), overrides the product price.
+type VariantInput struct {
+	Sku      string            `json:"sku" validate:"required,max=64"`
+	Options  map[string]string `json:"options" validate:"required,min=1"`
+	Price    *int64            `json:"price" validate:"omitnil,gt=0"`
+	Stock    uint              `json:"stock"`
+	ImageUrl string            `json:"image_url" validate:"omitempty,http_url,max=2048"`
+}
+
+// VariantUpdateInput changes the given fields of a variant. A price of zero
+// goes back to the product price.
+type VariantUpdateInput struct {
+	Sku      *string `json:"sku" validate:"omitnil,min=1,max=64"`
+	Price    *int64  `json:"price" validate:"omitnil,gte=0"`
+	Stock    *uint   `json:"stock"`
+	ImageUrl *string `json:"image_url" validate:"omitnil,max=2048,len=0|http_url"`
+	Active   *bool   `json:"active"`
+}
+
+// VariantMatrixInput creates a variant for every combination of option
+// values the product doesn't have yet. SKUs are SkuPrefix followed by the
+// option values, e.g. TSHIRT-M-RED.
+type VariantMatrixInput struct {
+	SkuPrefix string `json:"sku_prefix" validate:"required,max=32"`
+	Price     *int64 `json:"price" validate:"omitnil,gt=0"`
+	Stock     uint   `json:"stock"`
+}
+
+// ProductDimensionsInput sets the weight, in grams, and the packed size, in
+// millimetres, shipping is quoted with.
+type ProductDimensionsInput struct {
+	Weight uint `json:"weight" validate:"lte=1000000"`
+	Length uint `json:"length" validate:"lte=10000"`
+	Width  uint `json:"width" validate:"lte=10000"`
+	Height uint `json:"height" validate:"lte=10000"`
+}
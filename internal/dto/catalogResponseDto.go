@@ -0,0 +1,227 @@
+package dto
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+type ProductOptionResponse struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// VariantResponse shows the price the variant sells at, whether it comes
+// from the variant or the product.
+type VariantResponse struct {
+	ID        uint              `json:"id"`
+	Sku       string            `json:"sku"`
+	Name      string            `json:"name"`
+	Options   map[string]string `json:"options"`
+	Price     money.Money       `json:"price"`
+	Stock     uint              `json:"stock"`
+	Available uint              `json:"available"`
+	ImageUrl  string            `json:"image_url"`
+	Active    bool              `json:"active"`
+}
+
+type ProductImageResponse struct {
+	ID           uint   `json:"id"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+}
+
+// DimensionsResponse is the packed size of a product in millimetres.
+type DimensionsResponse struct {
+	Length uint `json:"length"`
+	Width  uint `json:"width"`
+	Height uint `json:"height"`
+}
+
+type ProductResponse struct {
+	ID          uint                    `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	CategoryId  uint                    `json:"category_id"`
+	ImageUrl    string                  `json:"image_url"`
+	Price       money.Money             `json:"price"`
+	SellerId    uint                    `json:"seller_id"`
+	Stock       uint                    `json:"stock"`
+	Weight      uint                    `json:"weight"`
+	Dimensions  DimensionsResponse      `json:"dimensions"`
+	Options     []ProductOptionResponse `json:"options,omitempty"`
+	Variants    []VariantResponse       `json:"variants,omitempty"`
+	Images      []ProductImageResponse  `json:"images"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+type CategoryResponse struct {
+	ID           uint              `json:"id"`
+	Name         string            `json:"name"`
+	ParentId     uint              `json:"parent_id"`
+	ImageUrl     string            `json:"image_url"`
+	DisplayOrder int               `json:"display_order"`
+	TaxClass     string            `json:"tax_class"`
+	Products     []ProductResponse `json:"products,omitempty"`
+}
+
+func NewProductResponse(p domain.Product) ProductResponse {
+	return ProductResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		CategoryId:  p.CategoryId,
+		ImageUrl:    p.ImageUrl,
+		Price:       p.Price,
+		SellerId:    uint(p.UserId),
+		Stock:       p.Stock,
+		Weight:      p.Weight,
+		Dimensions:  DimensionsResponse{Length: p.Length, Width: p.Width, Height: p.Height},
+		Options:     newProductOptionResponses(p.Options),
+		Variants:    NewVariantResponses(p, p.Variants),
+		Images:      NewProductImageResponses(p.Images),
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func newProductOptionResponses(options []domain.ProductOption) []ProductOptionResponse {
+	res := make([]ProductOptionResponse, 0, len(options))
+	for _, o := range options {
+		res = append(res, ProductOptionResponse{Name: o.Name, Values: o.Values})
+	}
+
+	return res
+}
+
+func NewProductImageResponse(i domain.ProductImage) ProductImageResponse {
+	return ProductImageResponse{
+		ID:           i.ID,
+		Url:          i.Url,
+		ThumbnailUrl: i.ThumbnailUrl,
+		Width:        i.Width,
+		Height:       i.Height,
+		Position:     i.Position,
+	}
+}
+
+func NewProductImageResponses(images []domain.ProductImage) []ProductImageResponse {
+	res := make([]ProductImageResponse, 0, len(images))
+	for _, i := range images {
+		res = append(res, NewProductImageResponse(i))
+	}
+
+	return res
+}
+
+func NewVariantResponse(p domain.Product, v domain.ProductVariant) VariantResponse {
+	return VariantResponse{
+		ID:        v.ID,
+		Sku:       v.Sku,
+		Name:      v.Label(p.Options),
+		Options:   v.Options,
+		Price:     v.PriceFor(p),
+		Stock:     v.Stock,
+		Available: v.Available(),
+		ImageUrl:  v.ImageUrl,
+		Active:    v.Active,
+	}
+}
+
+// NewVariantResponses needs the product for its options and base price.
+func NewVariantResponses(p domain.Product, variants []domain.ProductVariant) []VariantResponse {
+	res := make([]VariantResponse, 0, len(variants))
+	for _, v := range variants {
+		res = append(res, NewVariantResponse(p, v))
+	}
+
+	return res
+}
+
+// NewProductResponsesFrom is NewProductResponses for the pointer slices the
+// repositories return.
+func NewProductResponsesFrom(products []*domain.Product) []ProductResponse {
+	res := make([]ProductResponse, 0, len(products))
+	for _, p := range products {
+		res = append(res, NewProductResponse(*p))
+	}
+
+	return res
+}
+
+func NewProductResponses(products []domain.Product) []ProductResponse {
+	res := make([]ProductResponse, 0, len(products))
+	for _, p := range products {
+		res = append(res, NewProductResponse(p))
+	}
+
+	return res
+}
+
+func NewCategoryResponse(c domain.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:           c.ID,
+		Name:         c.Name,
+		ParentId:     c.ParentId,
+		ImageUrl:     c.ImageUrl,
+		DisplayOrder: c.DisplayOrder,
+		TaxClass:     c.TaxClass,
+		Products:     NewProductResponses(c.Products),
+	}
+}
+
+func NewCategoryResponses(categories []*domain.Category) []CategoryResponse {
+	res := make([]CategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		res = append(res, NewCategoryResponse(*c))
+	}
+
+	return res
+}
+
+// In shows the prices in another currency. Prices that can't be converted
+// stay in their own currency.
+func (r ProductResponse) In(rates money.Rates, currency string) ProductResponse {
+	r.Price = displayPrice(r.Price, rates, currency)
+
+	variants := make([]VariantResponse, 0, len(r.Variants))
+	for _, v := range r.Variants {
+		v.Price = displayPrice(v.Price, rates, currency)
+		variants = append(variants, v)
+	}
+	r.Variants = variants
+
+	return r
+}
+
+func (r CategoryResponse) In(rates money.Rates, currency string) CategoryResponse {
+	products := make([]ProductResponse, 0, len(r.Products))
+	for _, p := range r.Products {
+		products = append(products, p.In(rates, currency))
+	}
+	r.Products = products
+
+	return r
+}
+
+func ProductResponsesIn(products []ProductResponse, rates money.Rates, currency string) []ProductResponse {
+	res := make([]ProductResponse, 0, len(products))
+	for _, p := range products {
+		res = append(res, p.In(rates, currency))
+	}
+
+	return res
+}
+
+func displayPrice(price money.Money, rates money.Rates, currency string) money.Money {
+	converted, err := rates.Convert(price, currency)
+	if err != nil {
+		return price
+	}
+
+	return converted
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// CouponInput describes a coupon. Percent is required for percentage
+// coupons and Amount, in minor units of the store currency like
+// MinSubtotal, for fixed ones. The id lists scope the coupon to some
+// categories, sellers or products. Codes are not case sensitive.
+type CouponInput struct {
+	Code         string     `json:"code" validate:"required,coupon_code"`
+	Type         string     `json:"type" validate:"required,oneof=percentage fixed free_shipping"`
+	Percent      uint       `json:"percent" validate:"required_if=Type percentage,lte=100"`
+	Amount       int64      `json:"amount" validate:"required_if=Type fixed,gte=0"`
+	MinSubtotal  int64      `json:"min_subtotal" validate:"gte=0"`
+	CategoryIds  []uint     `json:"category_ids" validate:"max=100,dive,gt=0"`
+	SellerIds    []uint     `json:"seller_ids" validate:"max=100,dive,gt=0"`
+	ProductIds   []uint     `json:"product_ids" validate:"max=100,dive,gt=0"`
+	UsageLimit   *uint      `json:"usage_limit" validate:"omitnil,gt=0"`
+	PerUserLimit *uint      `json:"per_user_limit" validate:"omitnil,gt=0"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Active       *bool      `json:"active"`
+}
+
+// CouponCodeInput applies a coupon to the cart.
+type CouponCodeInput struct {
+	Code string `json:"code" validate:"required,max=50"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+// CouponResponse is the admin view of a coupon, in the shape of CouponInput
+// with the uses so far.
+type CouponResponse struct {
+	ID           uint        `json:"id"`
+	Code         string      `json:"code"`
+	Type         string      `json:"type"`
+	Percent      uint        `json:"percent"`
+	Amount       money.Money `json:"amount"`
+	MinSubtotal  money.Money `json:"min_subtotal"`
+	CategoryIds  []uint      `json:"category_ids"`
+	SellerIds    []uint      `json:"seller_ids"`
+	ProductIds   []uint      `json:"product_ids"`
+	UsageLimit   *uint       `json:"usage_limit"`
+	PerUserLimit *uint       `json:"per_user_limit"`
+	Used         uint        `json:"used"`
+	StartsAt     *time.Time  `json:"starts_at"`
+	EndsAt       *time.Time  `json:"ends_at"`
+	Active       bool        `json:"active"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+func NewCouponResponse(c domain.Coupon) CouponResponse {
+	return CouponResponse{
+		ID:           c.ID,
+		Code:         c.Code,
+		Type:         c.Type,
+		Percent:      c.Percent,
+		Amount:       c.Amount,
+		MinSubtotal:  c.MinSubtotal,
+		CategoryIds:  idList(c.CategoryIDs),
+		SellerIds:    idList(c.SellerIDs),
+		ProductIds:   idList(c.ProductIDs),
+		UsageLimit:   c.UsageLimit,
+		PerUserLimit: c.PerUserLimit,
+		Used:         c.Used,
+		StartsAt:     c.StartsAt,
+		EndsAt:       c.EndsAt,
+		Active:       c.Active,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func NewCouponResponses(coupons []*domain.Coupon) []CouponResponse {
+	res := make([]CouponResponse, 0, len(coupons))
+	for _, c := range coupons {
+		res = append(res, NewCouponResponse(*c))
+	}
+
+	return res
+}
+
+// idList shows a scope that isn't set as an empty list rather than null.
+func idList(ids []uint) []uint {
+	if ids == nil {
+		return []uint{}
+	}
+	return ids
+}
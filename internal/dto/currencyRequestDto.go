@@ -0,0 +1,10 @@
+package dto
+
+import "go-ecommerce-app/pkg/money"
+
+// CurrencyRatesInput sets the rates of the given currencies, as units of
+// each one unit of the store currency buys, e.g. {"USD": "1.0842"}.
+// Currencies left out keep their rate.
+type CurrencyRatesInput struct {
+	Rates map[string]money.Rate `json:"rates" validate:"required,min=1,max=200"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"time"
+)
+
+type LedgerEntryResponse struct {
+	ID        uint        `json:"id"`
+	OrderId   uint        `json:"order_id"`
+	Type      string      `json:"type"`
+	Amount    money.Money `json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type LedgerResponse struct {
+	Balances []money.Money         `json:"balances"`
+	Entries  []LedgerEntryResponse `json:"entries"`
+}
+
+func NewLedgerResponse(balances []money.Money, entries []*domain.LedgerEntry) LedgerResponse {
+	res := LedgerResponse{
+		Balances: balances,
+		Entries:  make([]LedgerEntryResponse, 0, len(entries)),
+	}
+
+	if res.Balances == nil {
+		res.Balances = []money.Money{}
+	}
+
+	for _, e := range entries {
+		res.Entries = append(res.Entries, LedgerEntryResponse{
+			ID:        e.ID,
+			OrderId:   e.OrderID,
+			Type:      e.Type,
+			Amount:    e.Amount,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,12 @@
+package dto
+
+// ImageUpload is one uploaded file, read from a multipart form.
+type ImageUpload struct {
+	Filename string
+	Data     []byte
+}
+
+// ImageOrderInput lists every image id of a product in the new order.
+type ImageOrderInput struct {
+	ImageIds []uint `json:"image_ids" validate:"required,min=1,unique"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+// CartItemInput sets the quantity of a product in the cart; a quantity of
+// zero removes it. VariantId is required for products with variants.
+type CartItemInput struct {
+	ProductId uint  `json:"product_id" validate:"required"`
+	VariantId *uint `json:"variant_id" validate:"omitnil,gt=0"`
+	Qty       uint  `json:"qty" validate:"lte=100"`
+}
+
+// CheckoutInput picks one of the cart's shipping options.
+type CheckoutInput struct {
+	ShippingMethodId uint `json:"shipping_method_id" validate:"required"`
+}
+
+// ShipmentInput records a parcel a seller sent. Without Items it holds every
+// unit of the seller's items that hasn't shipped yet.
+type ShipmentInput struct {
+	Carrier        string              `json:"carrier" validate:"required,max=100"`
+	TrackingNumber string              `json:"tracking_number" validate:"required,max=100"`
+	TrackingUrl    string              `json:"tracking_url" validate:"omitempty,http_url,max=2048"`
+	Items          []ShipmentItemInput `json:"items" validate:"max=100,unique=OrderItemId,dive"`
+}
+
+type ShipmentItemInput struct {
+	OrderItemId uint `json:"order_item_id" validate:"required"`
+	Qty         uint `json:"qty" validate:"required"`
+}
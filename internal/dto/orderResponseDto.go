@@ -0,0 +1,284 @@
+package dto
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"go-ecommerce-app/pkg/payments"
+	"go-ecommerce-app/pkg/tax"
+	"time"
+)
+
+type CartItemResponse struct {
+	ProductId   uint        `json:"product_id"`
+	VariantId   *uint       `json:"variant_id,omitempty"`
+	Sku         string      `json:"sku,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	ImageUrl    string      `json:"image_url"`
+	Price       money.Money `json:"price"`
+	Qty         uint        `json:"qty"`
+	Available   uint        `json:"available"`
+}
+
+type CartResponse struct {
+	Items  []CartItemResponse  `json:"items"`
+	Coupon *CartCouponResponse `json:"coupon,omitempty"`
+	Total  money.Money         `json:"total"`
+}
+
+// CartCouponResponse is the coupon applied to the cart and what it takes
+// off the items. If it no longer applies, Error says why; nothing is taken
+// off and checkout fails until the coupon is removed.
+type CartCouponResponse struct {
+	Code         string      `json:"code"`
+	Type         string      `json:"type"`
+	Discount     money.Money `json:"discount"`
+	FreeShipping bool        `json:"free_shipping"`
+	ErrorCode    string      `json:"error_code,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// ShippingOptionResponse is a shipping method the cart can be sent with and
+// what it costs.
+type ShippingOptionResponse struct {
+	MethodId uint        `json:"method_id"`
+	Name     string      `json:"name"`
+	Zone     string      `json:"zone"`
+	Cost     money.Money `json:"cost"`
+}
+
+type OrderItemResponse struct {
+	ID          uint        `json:"id"`
+	ProductId   uint        `json:"product_id"`
+	VariantId   *uint       `json:"variant_id,omitempty"`
+	SellerId    uint        `json:"seller_id"`
+	Sku         string      `json:"sku,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	ImageUrl    string      `json:"image_url"`
+	Price       money.Money `json:"price"`
+	Qty         uint        `json:"qty"`
+	TaxClass    string      `json:"tax_class"`
+	TaxRate     tax.Rate    `json:"tax_rate"`
+	Tax         money.Money `json:"tax"`
+	Discount    money.Money `json:"discount"`
+	ShippedQty  uint        `json:"shipped_qty"`
+}
+
+// FulfilmentResponse is a seller's part of an order and the parcels they
+// sent for it.
+type FulfilmentResponse struct {
+	SellerId  uint               `json:"seller_id"`
+	Status    string             `json:"status"`
+	Shipments []ShipmentResponse `json:"shipments"`
+}
+
+type ShipmentResponse struct {
+	ID             uint                   `json:"id"`
+	Carrier        string                 `json:"carrier"`
+	TrackingNumber string                 `json:"tracking_number"`
+	TrackingUrl    string                 `json:"tracking_url,omitempty"`
+	Items          []ShipmentItemResponse `json:"items"`
+	ShippedAt      time.Time              `json:"shipped_at"`
+}
+
+type ShipmentItemResponse struct {
+	OrderItemId uint `json:"order_item_id"`
+	Qty         uint `json:"qty"`
+}
+
+type OrderResponse struct {
+	ID           uint                 `json:"id"`
+	Reference    string               `json:"reference"`
+	Status       string               `json:"status"`
+	Amount       money.Money          `json:"amount"`
+	BaseCurrency string               `json:"base_currency"`
+	ExchangeRate money.Rate           `json:"exchange_rate"`
+	Tax          money.Money          `json:"tax"`
+	TaxInclusive bool                 `json:"tax_inclusive"`
+	Shipping     money.Money          `json:"shipping"`
+	ShippingName string               `json:"shipping_method"`
+	CouponCode   string               `json:"coupon_code,omitempty"`
+	Discount     money.Money          `json:"discount"`
+	Items        []OrderItemResponse  `json:"items"`
+	Fulfilments  []FulfilmentResponse `json:"fulfilments"`
+	ExpiresAt    time.Time            `json:"expires_at"`
+	PaidAt       *time.Time           `json:"paid_at"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+// CheckoutResponse carries the order and what the client needs to complete
+// its payment with the provider.
+type CheckoutResponse struct {
+	Order   OrderResponse     `json:"order"`
+	Payment *payments.Payment `json:"payment"`
+}
+
+func NewCartResponse(items []*domain.CartItem) CartResponse {
+	res := CartResponse{Items: make([]CartItemResponse, 0, len(items))}
+	for _, item := range items {
+		line := CartItemResponse{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Name:      item.Product.Name,
+			ImageUrl:  item.Product.ImageUrl,
+			Price:     item.Price(),
+			Qty:       item.Qty,
+			Available: item.Available(),
+		}
+
+		if item.Variant != nil {
+			line.Sku = item.Variant.Sku
+			line.VariantName = item.Variant.Label(item.Product.Options)
+			if item.Variant.ImageUrl != "" {
+				line.ImageUrl = item.Variant.ImageUrl
+			}
+		}
+
+		res.Items = append(res.Items, line)
+
+		// a product's currency may have lost its rate since it was added;
+		// such lines can't be totalled and checkout rejects them
+		if res.Total.SameCurrency(line.Price) {
+			res.Total = res.Total.Add(line.Price.Mul(int64(item.Qty)))
+		}
+	}
+
+	return res
+}
+
+// In shows the cart in another currency. Unit prices are converted the way
+// checkout converts them, so the total matches the order.
+func (r CartResponse) In(rates money.Rates, currency string) CartResponse {
+	res := CartResponse{Items: make([]CartItemResponse, 0, len(r.Items))}
+	for _, line := range r.Items {
+		line.Price = displayPrice(line.Price, rates, currency)
+		res.Items = append(res.Items, line)
+
+		if res.Total.SameCurrency(line.Price) {
+			res.Total = res.Total.Add(line.Price.Mul(int64(line.Qty)))
+		}
+	}
+
+	return res
+}
+
+// WithCoupon shows the coupon applied to the cart, taking discount off the
+// total, or the reason it doesn't apply.
+func (r CartResponse) WithCoupon(coupon domain.Coupon, discount domain.Discount, problem *domain.Error) CartResponse {
+	r.Coupon = &CartCouponResponse{Code: coupon.Code, Type: coupon.Type}
+
+	if problem != nil {
+		r.Coupon.ErrorCode = problem.Code
+		r.Coupon.Error = problem.Message
+		return r
+	}
+
+	r.Coupon.Discount = discount.Total()
+	r.Coupon.FreeShipping = coupon.Type == domain.CouponFreeShipping
+	if r.Total.SameCurrency(r.Coupon.Discount) {
+		r.Total = r.Total.Sub(r.Coupon.Discount)
+	}
+
+	return r
+}
+
+func NewOrderResponse(o domain.Order) OrderResponse {
+	items := make([]OrderItemResponse, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, OrderItemResponse{
+			ID:          item.ID,
+			ProductId:   item.ProductID,
+			VariantId:   item.VariantID,
+			SellerId:    item.SellerID,
+			Sku:         item.Sku,
+			Name:        item.Name,
+			VariantName: item.VariantName,
+			ImageUrl:    item.ImageUrl,
+			Price:       item.Price,
+			Qty:         item.Qty,
+			TaxClass:    item.TaxClass,
+			TaxRate:     item.TaxRate,
+			Tax:         item.Tax,
+			Discount:    item.Discount,
+			ShippedQty:  item.ShippedQty,
+		})
+	}
+
+	fulfilments := make([]FulfilmentResponse, 0, len(o.Fulfilments))
+	for _, f := range o.Fulfilments {
+		fulfilments = append(fulfilments, FulfilmentResponse{
+			SellerId:  f.SellerID,
+			Status:    f.Status,
+			Shipments: NewShipmentResponses(f.Shipments),
+		})
+	}
+
+	return OrderResponse{
+		ID:           o.ID,
+		Reference:    o.Reference,
+		Status:       o.Status,
+		Amount:       o.Amount,
+		BaseCurrency: o.BaseCurrency,
+		ExchangeRate: o.ExchangeRate,
+		Tax:          o.Tax,
+		TaxInclusive: o.TaxInclusive,
+		Shipping:     o.Shipping,
+		ShippingName: o.ShippingMethod,
+		CouponCode:   o.CouponCode,
+		Discount:     o.Discount,
+		Items:        items,
+		Fulfilments:  fulfilments,
+		ExpiresAt:    o.ExpiresAt,
+		PaidAt:       o.PaidAt,
+		CreatedAt:    o.CreatedAt,
+	}
+}
+
+func NewOrderResponses(orders []*domain.Order) []OrderResponse {
+	res := make([]OrderResponse, 0, len(orders))
+	for _, o := range orders {
+		res = append(res, NewOrderResponse(*o))
+	}
+
+	return res
+}
+
+func NewShipmentResponse(s domain.Shipment) ShipmentResponse {
+	items := make([]ShipmentItemResponse, 0, len(s.Items))
+	for _, item := range s.Items {
+		items = append(items, ShipmentItemResponse{OrderItemId: item.OrderItemID, Qty: item.Qty})
+	}
+
+	return ShipmentResponse{
+		ID:             s.ID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		TrackingUrl:    s.TrackingUrl,
+		Items:          items,
+		ShippedAt:      s.CreatedAt,
+	}
+}
+
+func NewShipmentResponses(shipments []domain.Shipment) []ShipmentResponse {
+	res := make([]ShipmentResponse, 0, len(shipments))
+	for _, s := range shipments {
+		res = append(res, NewShipmentResponse(s))
+	}
+
+	return res
+}
+
+func NewShippingOptionResponses(quotes []domain.ShippingQuote) []ShippingOptionResponse {
+	res := make([]ShippingOptionResponse, 0, len(quotes))
+	for _, q := range quotes {
+		res = append(res, ShippingOptionResponse{
+			MethodId: q.Method.ID,
+			Name:     q.Method.Name,
+			Zone:     q.Zone.Name,
+			Cost:     q.Cost,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"encoding/json"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secretPaymentId is the provider's id of the order's payment, which only
+// the server uses to match webhooks to orders.
+const secretPaymentId = "pi_do-not-leak-this-id"
+
+func secretOrder() domain.Order {
+	paidAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	shipment := domain.Shipment{
+		ID:             4,
+		FulfilmentID:   3,
+		OrderID:        9,
+		Carrier:        "CTT",
+		TrackingNumber: "RR123456789PT",
+		Items:          []domain.ShipmentItem{{ID: 1, ShipmentID: 4, OrderItemID: 2, Qty: 1}},
+	}
+
+	return domain.Order{
+		ID:           9,
+		UserID:       secretUser().ID,
+		Reference:    "ORD-9",
+		Status:       domain.OrderPaid,
+		Amount:       money.New(2598, "EUR"),
+		BaseCurrency: "EUR",
+		Tax:          money.New(486, "EUR"),
+		TaxInclusive: true,
+		TaxCountry:   "PT",
+		CouponCode:   "SPRING10",
+		Discount:     money.New(0, "EUR"),
+		PaymentID:    secretPaymentId,
+		PaidAt:       &paidAt,
+		Items: []domain.OrderItem{{
+			ID:        2,
+			OrderID:   9,
+			ProductID: 3,
+			SellerID:  7,
+			Name:      "Mug",
+			Price:     money.New(1299, "EUR"),
+			Qty:       2,
+			Tax:       money.New(486, "EUR"),
+		}},
+		Fulfilments: []domain.Fulfilment{{ID: 3, OrderID: 9, SellerID: 7, Status: domain.FulfilmentShipped, Shipments: []domain.Shipment{shipment}}},
+	}
+}
+
+func assertNoPaymentId(t *testing.T, name string, v any) {
+	t.Helper()
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: marshal: %v", name, err)
+	}
+
+	if strings.Contains(string(body), secretPaymentId) || strings.Contains(string(body), "payment_id") {
+		t.Errorf("%s exposes the payment id: %s", name, body)
+	}
+}
+
+func TestOrderResponsesOmitSecrets(t *testing.T) {
+	order := secretOrder()
+
+	assertNoSecrets(t, "OrderResponse", NewOrderResponse(order))
+	assertNoSecrets(t, "OrderResponses", NewOrderResponses([]*domain.Order{&order}))
+	assertNoSecrets(t, "ShipmentResponse", NewShipmentResponse(order.Fulfilments[0].Shipments[0]))
+
+	assertNoPaymentId(t, "OrderResponse", NewOrderResponse(order))
+	assertNoPaymentId(t, "OrderResponses", NewOrderResponses([]*domain.Order{&order}))
+
+	// a seller's part of the order is shown to them, not just to the buyer
+	assertNoSecrets(t, "seller OrderResponse", NewOrderResponse(order.ForSeller(7)))
+	assertNoPaymentId(t, "seller OrderResponse", NewOrderResponse(order.ForSeller(7)))
+}
+
+func TestCartResponseOmitsSecrets(t *testing.T) {
+	user := secretUser()
+	product := domain.Product{
+		ID:       3,
+		Name:     "Mug",
+		UserId:   int(user.ID),
+		Price:    money.New(1299, "EUR"),
+		Stock:    4,
+		Options:  []domain.ProductOption{{ID: 1, ProductID: 3, Name: "Colour", Values: []string{"red"}}},
+		Variants: []domain.ProductVariant{{ID: 2, ProductID: 3, Sku: "MUG-RED", Options: map[string]string{"Colour": "red"}, Active: true}},
+	}
+	variant := product.Variants[0]
+	items := []*domain.CartItem{
+		{ID: 1, UserID: user.ID, ProductID: 3, Qty: 1, Product: product},
+		{ID: 2, UserID: user.ID, ProductID: 3, VariantID: &variant.ID, Qty: 2, Product: product, Variant: &variant},
+	}
+
+	res := NewCartResponse(items)
+	assertNoSecrets(t, "CartResponse", res)
+
+	rates := money.NewRates("EUR", map[string]money.Rate{"USD": money.One})
+	assertNoSecrets(t, "CartResponse in USD", res.In(rates, "USD"))
+}
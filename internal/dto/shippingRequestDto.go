@@ -0,0 +1,27 @@
+package dto
+
+// ShippingAreaInput is a country, "*" for anywhere else, or the postcodes
+// of a country starting with PostcodeFrom, or with anything from
+// PostcodeFrom to PostcodeTo, e.g. "1000" to "1999".
+type ShippingAreaInput struct {
+	Country      string `json:"country" validate:"required,iso3166_1_alpha2|eq=*"`
+	PostcodeFrom string `json:"postcode_from" validate:"max=10"`
+	PostcodeTo   string `json:"postcode_to" validate:"max=10"`
+}
+
+type ShippingZoneInput struct {
+	Name  string              `json:"name" validate:"required,max=100"`
+	Areas []ShippingAreaInput `json:"areas" validate:"required,min=1,max=500,dive"`
+}
+
+// ShippingMethodInput prices a method in minor units of the store currency.
+// Flat methods cost Price; weight methods add PricePerKg for every started
+// kilogram. With FreeOver, carts worth at least that much ship for free.
+type ShippingMethodInput struct {
+	Name       string `json:"name" validate:"required,max=100"`
+	Rate       string `json:"rate" validate:"required,oneof=flat weight"`
+	Price      int64  `json:"price" validate:"gte=0"`
+	PricePerKg int64  `json:"price_per_kg" validate:"gte=0"`
+	FreeOver   *int64 `json:"free_over" validate:"omitnil,gte=0"`
+	Active     *bool  `json:"active"`
+}
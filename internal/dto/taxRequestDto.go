@@ -0,0 +1,19 @@
+package dto
+
+import "go-ecommerce-app/pkg/tax"
+
+// TaxRateInput is a row of the local tax table. Rate is a percentage such
+// as 23 or "5.5". Without a Region the rate covers the whole country, and
+// without a TaxClass it applies to the standard class.
+type TaxRateInput struct {
+	Country  string    `json:"country" validate:"required,iso3166_1_alpha2"`
+	Region   string    `json:"region" validate:"max=100"`
+	TaxClass string    `json:"tax_class" validate:"max=50"`
+	Name     string    `json:"name" validate:"max=100"`
+	Rate     *tax.Rate `json:"rate" validate:"required"`
+}
+
+// TaxClassInput sets the tax class of the products of a category.
+type TaxClassInput struct {
+	TaxClass string `json:"tax_class" validate:"required,max=50"`
+}
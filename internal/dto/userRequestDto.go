@@ -1,42 +1,86 @@
 package dto
 
+import "log/slog"
+
 type UserLogin struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6,max=72"`
+}
+
+// LogValue leaves the password out when the input is logged.
+func (u UserLogin) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("email", u.Email))
 }
 
 type UserSignup struct {
 	UserLogin
-	Phone string `json:"phone"`
+	Phone string `json:"phone" validate:"required,phone"`
+}
+
+func (u UserSignup) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("email", u.Email),
+		slog.String("phone", u.Phone),
+	)
 }
 
 type VerificationCodeInput struct {
-	Code int `json:"code"`
+	Code int `json:"code" validate:"required,gte=0,lte=999999"`
 }
 
 type GetVerificationCodeInput struct {
-	Email string `json:"string"`
+	Email string `json:"email" validate:"required,email"`
 }
 
 type SellerInput struct {
-	FirstName         string `json:"first_name"`
-	LastName          string `json:"last_name"`
-	PhoneNumber       string `json:"phone_number"`
-	BankAccountNumber uint   `json:"bank_account_number"`
-	SwiftCode         string `json:"swift_code"`
-	PaymentType       string `json:"payment_type"`
+	FirstName         string `json:"first_name" validate:"required,max=100"`
+	LastName          string `json:"last_name" validate:"required,max=100"`
+	PhoneNumber       string `json:"phone_number" validate:"required,phone"`
+	BankAccountNumber uint   `json:"bank_account_number" validate:"required"`
+	SwiftCode         string `json:"swift_code" validate:"required,bic"`
+	PaymentType       string `json:"payment_type" validate:"required,max=50"`
 }
 
+// AddressInput is an entry of the address book. Region is the state or
+// province, needed where tax rates differ within a country. PostCode is
+// checked against the country's format and stored the way it writes them.
 type AddressInput struct {
-	AddressLine1 string `json:"address_line1"`
-	AddressLine2 string `json:"address_line2"`
-	City         string `json:"city"`
-	PostCode     uint   `json:"post_code"`
-	Country      string `json:"country"`
+	AddressLine1    string `json:"address_line1" validate:"required,max=255"`
+	AddressLine2    string `json:"address_line2" validate:"max=255"`
+	City            string `json:"city" validate:"required,max=100"`
+	PostCode        string `json:"post_code" validate:"required,max=20"`
+	Country         string `json:"country" validate:"required,iso3166_1_alpha2"`
+	Region          string `json:"region" validate:"max=100"`
+	DefaultShipping bool   `json:"default_shipping"`
+	DefaultBilling  bool   `json:"default_billing"`
+}
+
+type AddressUpdateInput struct {
+	AddressLine1    *string `json:"address_line1" validate:"omitnil,min=1,max=255"`
+	AddressLine2    *string `json:"address_line2" validate:"omitnil,max=255"`
+	City            *string `json:"city" validate:"omitnil,min=1,max=100"`
+	PostCode        *string `json:"post_code" validate:"omitnil,min=1,max=20"`
+	Country         *string `json:"country" validate:"omitnil,iso3166_1_alpha2"`
+	Region          *string `json:"region" validate:"omitnil,max=100"`
+	DefaultShipping *bool   `json:"default_shipping"`
+	DefaultBilling  *bool   `json:"default_billing"`
 }
 
 type ProfileInput struct {
-	FirstName    string       `json:"first_name"`
-	LastName     string       `json:"last_name"`
-	AddressInput AddressInput `json:"address"`
+	FirstName    string        `json:"first_name" validate:"required,max=100"`
+	LastName     string        `json:"last_name" validate:"required,max=100"`
+	AddressInput *AddressInput `json:"address" validate:"omitnil"`
+}
+
+type WebhookEndpointInput struct {
+	Url         string   `json:"url" validate:"required,http_url"`
+	Description string   `json:"description" validate:"max=255"`
+	Events      []string `json:"events" validate:"required,min=1"`
+}
+
+type WebhookEndpointUpdateInput struct {
+	Url         *string  `json:"url" validate:"omitnil,http_url"`
+	Description *string  `json:"description" validate:"omitnil,max=255"`
+	Events      []string `json:"events" validate:"omitempty,min=1"`
+	Active      *bool    `json:"active"`
 }
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"time"
+)
+
+// Response DTOs are the only shapes handlers serialize. They deliberately
+// omit credentials and verification state (password hash, code, expiry) so
+// those can never leak through a response body.
+
+type AddressResponse struct {
+	ID              uint   `json:"id"`
+	AddressLine1    string `json:"address_line1"`
+	AddressLine2    string `json:"address_line2"`
+	City            string `json:"city"`
+	PostCode        string `json:"post_code"`
+	Country         string `json:"country"`
+	Region          string `json:"region,omitempty"`
+	DefaultShipping bool   `json:"default_shipping"`
+	DefaultBilling  bool   `json:"default_billing"`
+}
+
+type UserResponse struct {
+	ID        uint              `json:"id"`
+	FirstName string            `json:"first_name"`
+	LastName  string            `json:"last_name"`
+	Email     string            `json:"email"`
+	Phone     string            `json:"phone"`
+	Verified  bool              `json:"verified"`
+	UserType  string            `json:"user_type"`
+	Addresses []AddressResponse `json:"addresses"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// SellerResponse is the public view of a seller.
+type SellerResponse struct {
+	ID        uint   `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func NewAddressResponse(a domain.Address) AddressResponse {
+	return AddressResponse{
+		ID:              a.ID,
+		AddressLine1:    a.AddressLine1,
+		AddressLine2:    a.AddressLine2,
+		City:            a.City,
+		PostCode:        a.PostCode,
+		Country:         a.Country,
+		Region:          a.Region,
+		DefaultShipping: a.DefaultShipping,
+		DefaultBilling:  a.DefaultBilling,
+	}
+}
+
+func NewAddressResponses(addresses []domain.Address) []AddressResponse {
+	res := make([]AddressResponse, 0, len(addresses))
+	for _, a := range addresses {
+		res = append(res, NewAddressResponse(a))
+	}
+
+	return res
+}
+
+func NewUserResponse(u domain.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Phone:     u.Phone,
+		Verified:  u.Verified,
+		UserType:  u.UserType,
+		Addresses: NewAddressResponses(u.Addresses),
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func NewSellerResponse(u domain.User) SellerResponse {
+	return SellerResponse{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+	}
+}
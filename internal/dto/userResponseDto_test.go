@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"encoding/json"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secretKeys are the user fields no response may carry.
+var secretKeys = []string{"password", "code", "expiry"}
+
+const (
+	secretHash = "$2a$10$do-not-leak-this-hash"
+	secretCode = 731904
+)
+
+func secretUser() domain.User {
+	return domain.User{
+		ID:        7,
+		FirstName: "Ana",
+		LastName:  "Silva",
+		Email:     "ana@example.com",
+		Phone:     "+351912345678",
+		Password:  secretHash,
+		Code:      secretCode,
+		Expiry:    time.Date(2031, 2, 3, 4, 5, 6, 0, time.UTC),
+		Verified:  true,
+		UserType:  domain.SELLER,
+		Addresses: []domain.Address{{ID: 1, UserID: 7, AddressLine1: "Rua 1", City: "Lisboa", Country: "PT"}},
+	}
+}
+
+// assertNoSecrets fails if the JSON of v has a secret key at any depth, or
+// the secret values anywhere.
+func assertNoSecrets(t *testing.T, name string, v any) {
+	t.Helper()
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: marshal: %v", name, err)
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("%s: unmarshal: %v", name, err)
+	}
+
+	if path, ok := findSecretKey(decoded, name); ok {
+		t.Errorf("%s exposes %s: %s", name, path, body)
+	}
+
+	for _, value := range []string{secretHash, "731904", "2031-02-03"} {
+		if strings.Contains(string(body), value) {
+			t.Errorf("%s exposes %q: %s", name, value, body)
+		}
+	}
+}
+
+func findSecretKey(v any, path string) (string, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			for _, secret := range secretKeys {
+				if strings.EqualFold(key, secret) {
+					return path + "." + key, true
+				}
+			}
+			if p, ok := findSecretKey(child, path+"."+key); ok {
+				return p, true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if p, ok := findSecretKey(child, path+"[]"); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+func TestUserResponsesOmitSecrets(t *testing.T) {
+	user := secretUser()
+
+	assertNoSecrets(t, "UserResponse", NewUserResponse(user))
+	assertNoSecrets(t, "SellerResponse", NewSellerResponse(user))
+	assertNoSecrets(t, "AddressResponses", NewAddressResponses(user.Addresses))
+}
+
+func TestCatalogResponsesOmitSecrets(t *testing.T) {
+	user := secretUser()
+	product := domain.Product{
+		ID:       3,
+		Name:     "Mug",
+		UserId:   int(user.ID),
+		Price:    money.New(1299, "EUR"),
+		Stock:    4,
+		Options:  []domain.ProductOption{{ID: 1, ProductID: 3, Name: "Colour", Values: []string{"red"}}},
+		Variants: []domain.ProductVariant{{ID: 2, ProductID: 3, Sku: "MUG-RED", Options: map[string]string{"Colour": "red"}, Active: true}},
+		Images:   []domain.ProductImage{{ID: 5, ProductID: 3, Url: "/media/mug.jpg"}},
+	}
+	category := domain.Category{ID: 1, Name: "Kitchen", Products: []domain.Product{product}}
+
+	assertNoSecrets(t, "ProductResponse", NewProductResponse(product))
+	assertNoSecrets(t, "ProductResponses", NewProductResponses([]domain.Product{product}))
+	assertNoSecrets(t, "CategoryResponse", NewCategoryResponse(category))
+	assertNoSecrets(t, "CategoryResponses", NewCategoryResponses([]*domain.Category{&category}))
+}
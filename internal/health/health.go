@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOk       = "ok"
+	StatusFailing  = "failing"
+	StatusDisabled = "disabled"
+)
+
+type disabledError struct{ reason string }
+
+func (e disabledError) Error() string { return e.reason }
+
+// Disabled is returned by a check for a dependency that is not configured.
+// It is reported but does not make the service unhealthy.
+func Disabled(reason string) error {
+	return disabledError{reason: reason}
+}
+
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+type Result struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+func (r Report) Healthy() bool {
+	return r.Status == StatusOk
+}
+
+// Run executes all checks concurrently, each bounded by its own timeout,
+// and reports failing if any of them failed.
+func Run(ctx context.Context, checks []Check) Report {
+	report := Report{Status: StatusOk, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c Check) {
+			defer wg.Done()
+			res := runOne(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.Name] = res
+			if res.Status == StatusFailing {
+				report.Status = StatusFailing
+			}
+		}(c)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func runOne(ctx context.Context, c Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := Result{Status: StatusOk, DurationMs: time.Since(started).Milliseconds()}
+
+	if _, ok := err.(disabledError); ok {
+		res.Status = StatusDisabled
+		res.Error = err.Error()
+	} else if err != nil {
+		res.Status = StatusFailing
+		res.Error = err.Error()
+	}
+
+	return res
+}
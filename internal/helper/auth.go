@@ -3,8 +3,10 @@ package helper
 import (
 	"errors"
 	"fmt"
+	"go-ecommerce-app/config"
 	"go-ecommerce-app/internal/domain"
-	"log"
+	"go-ecommerce-app/internal/logging"
+	"log/slog"
 	"strings"
 
 	"time"
@@ -15,23 +17,24 @@ import (
 )
 
 type Auth struct {
-	Secret string
+	Secret   string
+	TokenTTL time.Duration
 }
 
-func SetupAuth(s string) Auth {
-	return Auth{Secret: s}
+func SetupAuth(cfg config.AuthConfig) Auth {
+	return Auth{Secret: cfg.Secret, TokenTTL: cfg.TokenTTL}
 }
 
 func (a Auth) CreateHashPassword(password string) (string, error) {
 	if len(password) < 6 {
-		return "", errors.New("password must be at least 6 characters long")
+		return "", domain.Validation("password_too_short", "password must be at least 6 characters long")
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
 	if err != nil {
 		// Log error
-		log.Printf("error while creating hash password: %v\n", err)
+		slog.Error("hash password failed", "error", err)
 		return "", err
 	}
 
@@ -40,13 +43,13 @@ func (a Auth) CreateHashPassword(password string) (string, error) {
 
 func (a Auth) VerifyPassword(plainPassword string, hash string) error {
 	if len(plainPassword) < 6 {
-		return errors.New("password must be at least 6 characters long")
+		return domain.ErrInvalidCredentials
 	}
 
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plainPassword))
 
 	if err != nil {
-		return errors.New("invalid password")
+		return domain.ErrInvalidCredentials
 	}
 
 	return nil
@@ -61,13 +64,13 @@ func (a Auth) GenerateToken(id uint, email string, role string) (string, error)
 		"user_id": id,
 		"email":   email,
 		"role":    role,
-		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(),
+		"exp":     time.Now().Add(a.TokenTTL).Unix(),
 	})
 
 	tokenString, err := token.SignedString([]byte(a.Secret))
 
 	if err != nil {
-		log.Printf("error while signing token: %v\n", err)
+		slog.Error("sign token failed", "error", err)
 		return "", errors.New("error signing token")
 	}
 
@@ -78,11 +81,11 @@ func (a Auth) VerifyToken(t string) (domain.User, error) {
 	tokenArr := strings.Split(t, " ")
 
 	if len(tokenArr) != 2 {
-		return domain.User{}, errors.New("invalid token format")
+		return domain.User{}, domain.Unauthorized("invalid_token", "invalid token format")
 	}
 
 	if tokenArr[0] != "Bearer" {
-		return domain.User{}, errors.New("invalid token type")
+		return domain.User{}, domain.Unauthorized("invalid_token", "invalid token type")
 	}
 
 	tokenStr := tokenArr[1]
@@ -96,12 +99,12 @@ func (a Auth) VerifyToken(t string) (domain.User, error) {
 	})
 
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, domain.Unauthorized("invalid_token", "invalid token").Wrap(err)
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		if float64(time.Now().Unix()) > claims["exp"].(float64) {
-			return domain.User{}, errors.New("token expired")
+			return domain.User{}, domain.Unauthorized("token_expired", "token expired")
 		}
 
 		user := domain.User{}
@@ -112,24 +115,47 @@ func (a Auth) VerifyToken(t string) (domain.User, error) {
 		return user, nil
 	}
 
-	return domain.User{}, errors.New("invalid token")
+	return domain.User{}, domain.Unauthorized("invalid_token", "invalid token")
 }
 
 func (a Auth) Authorize(ctx *fiber.Ctx) error {
 	authHeader := ctx.Get("Authorization")
 	user, err := a.VerifyToken(authHeader)
 
-	if err != nil || user.ID == 0 {
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"message": "unauthorized",
-			"reason":  err,
-		})
+	if err != nil {
+		return authError(ctx, err)
+	}
+
+	if user.ID == 0 {
+		return authError(ctx, domain.Unauthorized("invalid_token", "invalid token"))
 	}
 
 	ctx.Locals("user", user)
+	ctx.SetUserContext(logging.With(ctx.UserContext(), slog.Uint64(logging.UserIdKey, uint64(user.ID))))
 	return ctx.Next()
 }
 
+// authError writes err in the same body shape as rest.ErrorResponse; helper
+// cannot import rest without an import cycle.
+func authError(ctx *fiber.Ctx, err error) error {
+	status := fiber.StatusUnauthorized
+	appErr := domain.Unauthorized("unauthorized", "unauthorized")
+
+	var e *domain.Error
+	if errors.As(err, &e) {
+		appErr = e
+	}
+
+	if errors.Is(appErr, domain.ErrForbidden) {
+		status = fiber.StatusForbidden
+	}
+
+	return ctx.Status(status).JSON(fiber.Map{
+		"code":    appErr.Code,
+		"message": appErr.Message,
+	})
+}
+
 func (a Auth) GetCurrentUser(ctx *fiber.Ctx) domain.User {
 	user := ctx.Locals("user")
 	return user.(domain.User)
@@ -144,17 +170,39 @@ func (a Auth) AuthorizeSeller(ctx *fiber.Ctx) error {
 	user, err := a.VerifyToken(authHeader)
 
 	if err != nil {
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"message": "unauthorized",
-			"reason":  err,
-		})
-	} else if user.ID > 0 && user.UserType == domain.SELLER {
-		ctx.Locals("user", user)
-		return ctx.Next()
-	} else {
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"message": "unauthorized",
-			"reason":  errors.New("invalid user type"),
-		})
+		return authError(ctx, err)
+	}
+
+	if user.ID == 0 {
+		return authError(ctx, domain.Unauthorized("invalid_token", "invalid token"))
+	}
+
+	if user.UserType != domain.SELLER {
+		return authError(ctx, domain.ErrSellerRequired)
+	}
+
+	ctx.Locals("user", user)
+	ctx.SetUserContext(logging.With(ctx.UserContext(), slog.Uint64(logging.UserIdKey, uint64(user.ID))))
+	return ctx.Next()
+}
+
+func (a Auth) AuthorizeAdmin(ctx *fiber.Ctx) error {
+	authHeader := ctx.Get("Authorization")
+	user, err := a.VerifyToken(authHeader)
+
+	if err != nil {
+		return authError(ctx, err)
+	}
+
+	if user.ID == 0 {
+		return authError(ctx, domain.Unauthorized("invalid_token", "invalid token"))
+	}
+
+	if user.UserType != domain.ADMIN {
+		return authError(ctx, domain.ErrAdminRequired)
 	}
+
+	ctx.Locals("user", user)
+	ctx.SetUserContext(logging.With(ctx.UserContext(), slog.Uint64(logging.UserIdKey, uint64(user.ID))))
+	return ctx.Next()
 }
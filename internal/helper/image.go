@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ImageTypes are the content types accepted for uploaded images, by the
+// format name image.Decode reports.
+var ImageTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+}
+
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// ImageConfig reads the format and dimensions of an encoded image without
+// decoding the pixels, so oversized images can be rejected cheaply.
+func ImageConfig(data []byte) (format string, width int, height int, err error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, ErrUnsupportedImage
+	}
+
+	if _, ok := ImageTypes[format]; !ok {
+		return "", 0, 0, ErrUnsupportedImage
+	}
+
+	return format, cfg.Width, cfg.Height, nil
+}
+
+// Thumbnail scales the image down to fit in size x size pixels and encodes
+// it as JPEG, or as PNG if it has transparency. It returns the encoded
+// thumbnail, its content type and file extension.
+func Thumbnail(data []byte, size int) ([]byte, string, string, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", ErrUnsupportedImage
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if dst.Opaque() {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", "jpg", err
+	}
+
+	err = png.Encode(&buf, dst)
+	return buf.Bytes(), "image/png", "png", err
+}
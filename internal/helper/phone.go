@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// PhoneRules normalizes phone numbers and decides which ones we accept.
+type PhoneRules struct {
+	defaultCountry string
+	allowed        map[string]bool
+	blocked        []string
+}
+
+func NewPhoneRules(cfg config.SmsConfig) PhoneRules {
+	allowed := map[string]bool{}
+	for _, c := range cfg.AllowedCountries {
+		allowed[c] = true
+	}
+
+	return PhoneRules{
+		defaultCountry: cfg.DefaultCountry,
+		allowed:        allowed,
+		blocked:        cfg.BlockedPrefixes,
+	}
+}
+
+// Normalize returns phone in E.164 form (e.g. "+351912345678"). Numbers
+// without a country code are read as numbers of the default country. It
+// fails for numbers that are invalid, from a country that is not allowed,
+// premium-rate or shared-cost, or start with a blocked prefix.
+func (r PhoneRules) Normalize(phone string) (string, error) {
+	num, err := phonenumbers.Parse(strings.TrimSpace(phone), r.defaultCountry)
+	if err != nil || !phonenumbers.IsValidNumber(num) {
+		return "", domain.ErrInvalidPhone
+	}
+
+	if !r.allowed[phonenumbers.GetRegionCodeForNumber(num)] {
+		return "", domain.ErrPhoneNotAllowed
+	}
+
+	switch phonenumbers.GetNumberType(num) {
+	case phonenumbers.PREMIUM_RATE, phonenumbers.SHARED_COST:
+		return "", domain.ErrPhoneBlocked
+	}
+
+	e164 := phonenumbers.Format(num, phonenumbers.E164)
+	for _, prefix := range r.blocked {
+		if strings.HasPrefix(e164, prefix) {
+			return "", domain.ErrPhoneBlocked
+		}
+	}
+
+	return e164, nil
+}
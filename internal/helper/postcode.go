@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"go-ecommerce-app/internal/domain"
+	"regexp"
+	"strings"
+)
+
+// postcodeFormat is how a country writes its postcodes: the groups of
+// pattern, matched against the compact postcode, joined by sep.
+type postcodeFormat struct {
+	pattern *regexp.Regexp
+	sep     string
+}
+
+var postcodeFormats = map[string]postcodeFormat{
+	"PT": {regexp.MustCompile(`^(\d{4})(\d{3})$`), "-"},
+	"ES": {regexp.MustCompile(`^(\d{5})$`), ""},
+	"FR": {regexp.MustCompile(`^(\d{5})$`), ""},
+	"DE": {regexp.MustCompile(`^(\d{5})$`), ""},
+	"IT": {regexp.MustCompile(`^(\d{5})$`), ""},
+	"NL": {regexp.MustCompile(`^(\d{4})([A-Z]{2})$`), " "},
+	"PL": {regexp.MustCompile(`^(\d{2})(\d{3})$`), "-"},
+	"IE": {regexp.MustCompile(`^([A-Z]\d[\dW])([\dA-Z]{4})$`), " "},
+	"GB": {regexp.MustCompile(`^([A-Z]{1,2}\d[A-Z\d]?)(\d[A-Z]{2})$`), " "},
+	"US": {regexp.MustCompile(`^(\d{5})(\d{4})?$`), "-"},
+	"CA": {regexp.MustCompile(`^([A-Z]\d[A-Z])(\d[A-Z]\d)$`), " "},
+	"BR": {regexp.MustCompile(`^(\d{5})(\d{3})$`), "-"},
+	"JP": {regexp.MustCompile(`^(\d{3})(\d{4})$`), "-"},
+}
+
+// otherPostcode is what we accept for countries without a known format.
+var otherPostcode = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+
+// NormalizePostcode returns postcode the way country writes it, e.g.
+// "1000-001" for "1000001" in Portugal or "SW1A 1AA" for "sw1a1aa" in the
+// United Kingdom. Postcodes of other countries are upper cased with spaces
+// tidied up. It fails for postcodes that don't fit the country's format.
+func NormalizePostcode(country string, postcode string) (string, error) {
+	compact := domain.CompactPostcode(postcode)
+
+	format, ok := postcodeFormats[strings.ToUpper(country)]
+	if !ok {
+		if !otherPostcode.MatchString(compact) {
+			return "", domain.ErrInvalidPostcode
+		}
+		return strings.Join(strings.Fields(strings.ToUpper(postcode)), " "), nil
+	}
+
+	groups := format.pattern.FindStringSubmatch(compact)
+	if groups == nil {
+		return "", domain.ErrInvalidPostcode
+	}
+
+	var parts []string
+	for _, g := range groups[1:] {
+		if g != "" {
+			parts = append(parts, g)
+		}
+	}
+
+	return strings.Join(parts, format.sep), nil
+}
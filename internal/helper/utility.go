@@ -2,6 +2,7 @@ package helper
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"strconv"
 )
 
@@ -22,3 +23,14 @@ func RandomNumbers(length int) (int, error) {
 
 	return strconv.Atoi(string(buffer))
 }
+
+func RandomString(length int) (string, error) {
+	buffer := make([]byte, length)
+
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
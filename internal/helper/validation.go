@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationErrors maps a request field (by its json path, e.g.
+// "address.city") to a human readable message.
+type ValidationErrors map[string]string
+
+func (v ValidationErrors) Error() string {
+	fields := make([]string, 0, len(v))
+	for f := range v {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	msgs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f, v[f]))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// embeddedField names embedded structs so their segment can be dropped from
+// field paths, matching how their fields appear in the json body.
+const embeddedField = "~"
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{6,19}$`)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,50}$`)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// report fields by their json name instead of the go field name
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "" && f.Anonymous {
+			return embeddedField
+		}
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return phonePattern.MatchString(fl.Field().String())
+	})
+
+	v.RegisterValidation("coupon_code", func(fl validator.FieldLevel) bool {
+		return couponCodePattern.MatchString(fl.Field().String())
+	})
+
+	return v
+}
+
+// ValidateStruct runs the `validate` tags of s and returns ValidationErrors
+// when any of them fail.
+func ValidateStruct(s any) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	res := ValidationErrors{}
+	for _, fe := range fieldErrs {
+		res[fieldPath(fe)] = fieldMessage(fe)
+	}
+
+	return res
+}
+
+// fieldPath drops the struct name and embedded structs from the namespace,
+// turning "ProfileInput.address.city" into "address.city".
+func fieldPath(fe validator.FieldError) string {
+	segments := strings.Split(fe.Namespace(), ".")[1:]
+
+	path := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if s != embeddedField {
+			path = append(path, s)
+		}
+	}
+
+	return strings.Join(path, ".")
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "phone":
+		return "must be a valid phone number"
+	case "coupon_code":
+		return "must be 3 to 50 letters, digits, dashes or underscores"
+	case "url", "http_url":
+		return "must be a valid url"
+	case "bic":
+		return "must be a valid SWIFT/BIC code"
+	case "iso3166_1_alpha2":
+		return "must be a two letter ISO country code"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "unique":
+		return "must not contain duplicates"
+	case "gt", "gte", "lt", "lte":
+		return fmt.Sprintf("must be %s %s", comparisonWords[fe.Tag()], fe.Param())
+	}
+
+	return "is invalid"
+}
+
+var comparisonWords = map[string]string{
+	"gt":  "greater than",
+	"gte": "greater than or equal to",
+	"lt":  "less than",
+	"lte": "less than or equal to",
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/service"
+	"go-ecommerce-app/pkg/payments"
+	"go-ecommerce-app/pkg/storage"
+	"go-ecommerce-app/pkg/webhooks"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job is a unit of background work that can also be triggered by hand with
+// `jobs run <name>`.
+type Job struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context) error
+}
+
+func Registry(db *gorm.DB, cfg config.AppConfig) []Job {
+	webhookSvc := service.WebhookService{
+		Repo:   repository.NewWebhookRepository(db),
+		Sender: webhooks.NewSender(10 * time.Second),
+		Auth:   helper.SetupAuth(cfg.Auth),
+		Config: cfg,
+	}
+
+	orderSvc := service.OrderService{
+		Repo:     repository.NewOrderRepository(db),
+		Tx:       repository.NewUnitOfWork(db),
+		Payments: payments.NewProvider(cfg.Payments),
+		Webhooks: webhookSvc,
+		Currency: service.CurrencyService{
+			Repo:   repository.NewCurrencyRepository(db),
+			Auth:   helper.SetupAuth(cfg.Auth),
+			Config: cfg,
+		},
+		Auth:   helper.SetupAuth(cfg.Auth),
+		Config: cfg,
+	}
+
+	mediaSvc := service.MediaService{
+		Repo:   repository.NewMediaRepository(db),
+		Tx:     repository.NewUnitOfWork(db),
+		Auth:   helper.SetupAuth(cfg.Auth),
+		Config: cfg,
+	}
+
+	return []Job{
+		{
+			Name:        "webhooks",
+			Description: "send webhook deliveries that are due",
+			Run: func(ctx context.Context) error {
+				return webhookSvc.ProcessDueDeliveries(ctx)
+			},
+		},
+		{
+			Name:        "reservations",
+			Description: "expire unpaid orders and release their stock",
+			Run: func(ctx context.Context) error {
+				return orderSvc.ExpireOrders(ctx)
+			},
+		},
+		{
+			Name:        "media",
+			Description: "delete stored product images no longer in use",
+			Run: func(ctx context.Context) error {
+				store, err := storage.New(cfg.Media)
+				if err != nil {
+					return err
+				}
+
+				mediaSvc.Storage = store
+				_, err = mediaSvc.CleanupOrphans(ctx)
+				return err
+			},
+		},
+	}
+}
+
+func Find(jobs []Job, name string) (Job, bool) {
+	for _, j := range jobs {
+		if j.Name == name {
+			return j, true
+		}
+	}
+
+	return Job{}, false
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup installs the default slog logger described by cfg. The standard
+// library log package is routed through it as well, so any remaining
+// log.Printf calls come out in the same format.
+func Setup(cfg config.LogConfig) *slog.Logger {
+	logger := New(os.Stderr, cfg)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New builds a logger writing to w. Attributes stored on the context with
+// With are added to every record, and sensitive attributes are redacted.
+func New(w io.Writer, cfg config.LogConfig) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       ParseLevel(cfg.Level),
+		ReplaceAttr: redact,
+	}
+
+	var h slog.Handler
+	if cfg.Format == "text" {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(contextHandler{h})
+}
+
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+type ctxKey struct{}
+
+// With returns a copy of ctx carrying attrs, which are added to every record
+// logged with that context (e.g. slog.InfoContext(ctx, ...)).
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := Attrs(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, ctxKey{}, merged)
+}
+
+// Attrs returns the attributes stored on ctx by With.
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	return attrs
+}
+
+// RequestId returns the id of the request ctx belongs to, if any.
+func RequestId(ctx context.Context) string {
+	for _, a := range Attrs(ctx) {
+		if a.Key == RequestIdKey {
+			return a.Value.String()
+		}
+	}
+	return ""
+}
+
+// contextHandler adds the attributes stored on the record's context and the
+// ids of its current span, so log records can be joined with traces.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := Attrs(ctx)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs[:len(attrs):len(attrs)],
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	if len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
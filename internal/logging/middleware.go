@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIdHeader = "X-Request-ID"
+	RequestIdKey    = "request_id"
+	UserIdKey       = "user_id"
+)
+
+// maxRequestIdLength bounds ids taken from the client so they can't bloat
+// the logs.
+const maxRequestIdLength = 128
+
+// Middleware assigns every request an id, taken from the X-Request-ID header
+// when the client (or a proxy) sent a usable one and generated otherwise. The
+// id is echoed in the response header and attached to the request context,
+// so every log record of the request carries it, and an access log record is
+// written once the request completes.
+func Middleware(ctx *fiber.Ctx) error {
+	started := time.Now()
+
+	id := ctx.Get(RequestIdHeader)
+	if !validRequestId(id) {
+		id = uuid.NewString()
+	}
+
+	ctx.Set(RequestIdHeader, id)
+	ctx.Locals(RequestIdKey, id)
+	ctx.SetUserContext(With(ctx.UserContext(), slog.String(RequestIdKey, id)))
+
+	err := ctx.Next()
+
+	status := ctx.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+
+	level := slog.LevelInfo
+	if status >= fiber.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	// read the context again: the auth middleware adds the user id to it
+	slog.Log(ctx.UserContext(), level, "request",
+		"method", ctx.Method(),
+		"path", ctx.Path(),
+		"route", ctx.Route().Path,
+		"status", status,
+		"duration", time.Since(started),
+		"ip", ctx.IP(),
+	)
+
+	return err
+}
+
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are never logged, whatever the value.
+var sensitiveKeys = map[string]bool{
+	"code":          true,
+	"dsn":           true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+}
+
+// sensitiveParts redact any key containing them, e.g. "new_password" or
+// "webhook_secret".
+var sensitiveParts = []string{"password", "secret", "token", "api_key"}
+
+// redact is the ReplaceAttr hook: secrets are replaced outright and personal
+// data (emails, phone numbers) is masked so logs stay useful for support.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+
+	if isSensitive(key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	if a.Value.Kind() != slog.KindString {
+		return a
+	}
+
+	switch {
+	case key == "email" || strings.HasSuffix(key, "_email"):
+		return slog.String(a.Key, MaskEmail(a.Value.String()))
+	case key == "phone" || strings.HasSuffix(key, "_phone") || key == "to":
+		return slog.String(a.Key, MaskPhone(a.Value.String()))
+	}
+
+	return a
+}
+
+func isSensitive(key string) bool {
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskEmail keeps the first character of the local part and the domain:
+// "jane@example.com" becomes "j***@example.com".
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskPhone keeps only the last two digits: "+351912345678" becomes
+// "***********78".
+func MaskPhone(phone string) string {
+	if len(phone) <= 2 {
+		return redacted
+	}
+	return strings.Repeat("*", len(phone)-2) + phone[len(phone)-2:]
+}
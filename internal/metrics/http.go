@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests no route handled, so arbitrary paths don't
+// create new series.
+const unmatchedRoute = "unmatched"
+
+// Middleware records request counts and latency labelled with the route
+// template (e.g. "/seller/webhooks/:id") rather than the concrete path.
+func Middleware(ctx *fiber.Ctx) error {
+	started := time.Now()
+	own := ctx.Route()
+
+	err := ctx.Next()
+
+	status := ctx.Response().StatusCode()
+	if err != nil {
+		// the error handler runs after us and decides the final status
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+
+	route := ctx.Route().Path
+	if ctx.Route() == own {
+		route = unmatchedRoute
+	}
+
+	labels := []string{ctx.Method(), route, strconv.Itoa(status)}
+	HttpRequests.WithLabelValues(labels...).Inc()
+	HttpDuration.WithLabelValues(labels...).Observe(time.Since(started).Seconds())
+
+	return err
+}
+
+// Handler serves the registry in the prometheus text format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
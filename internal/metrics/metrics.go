@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Registry holds every metric exposed on /metrics. A dedicated registry keeps
+// collectors registered by libraries out of our output.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HttpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HttpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of GORM operations by operation and table.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "GORM operations that returned an error, excluding record not found.",
+	}, []string{"operation", "table"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected by a rate limit policy.",
+	}, []string{"policy"})
+)
+
+// Business events
+var (
+	Signups = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "signups_total",
+		Help: "Accounts created.",
+	})
+
+	VerificationsSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "verification_codes_sent_total",
+		Help: "Verification codes sent by SMS.",
+	})
+
+	LoginsFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "logins_failed_total",
+		Help: "Login attempts rejected because of invalid credentials.",
+	})
+
+	OrdersPlaced = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "orders_placed_total",
+		Help: "Orders created at checkout.",
+	})
+
+	PaymentFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "payment_failures_total",
+		Help: "Payments that failed or were declined.",
+	})
+
+	SmsSendFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sms_send_failures_total",
+		Help: "SMS messages the provider failed to send.",
+	})
+
+	SmsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sms_rejected_total",
+		Help: "SMS messages refused before sending, by reason (phone, number_cap, global_cap).",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HttpRequests,
+		HttpDuration,
+		DbQueryDuration,
+		DbQueryErrors,
+		RateLimited,
+		Signups,
+		VerificationsSent,
+		LoginsFailed,
+		OrdersPlaced,
+		PaymentFailures,
+		SmsSendFailures,
+		SmsRejected,
+	)
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, "postgres"))
+}
@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/metrics"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Policy is a named limit; the name namespaces its buckets so the same
+// client has separate buckets per policy.
+type Policy struct {
+	Name  string
+	Limit Limit
+}
+
+// KeyFunc identifies who a request is counted against, e.g. "ip:10.0.0.1".
+// It returns "" when the key does not apply to the request.
+type KeyFunc func(ctx *fiber.Ctx) string
+
+// Limiter builds middlewares enforcing the configured policies.
+type Limiter struct {
+	Store   Store
+	Enabled bool
+	Default Policy
+	Auth    Policy
+	Sms     Policy
+}
+
+func New(cfg config.RateLimitConfig, store Store) *Limiter {
+	return &Limiter{
+		Store:   store,
+		Enabled: cfg.Enabled,
+		Default: Policy{"default", Limit{cfg.Requests, cfg.Window}},
+		Auth:    Policy{"auth", Limit{cfg.Auth.Requests, cfg.Auth.Window}},
+		Sms:     Policy{"sms", Limit{cfg.Sms.Requests, cfg.Sms.Window}},
+	}
+}
+
+// localsKey holds the tightest result seen so far for the request, so the
+// headers describe the policy closest to rejecting it when several apply.
+const localsKey = "ratelimit"
+
+// Limit returns a middleware taking a token from the policy's bucket for
+// each key that applies. The request is rejected with 429 if any bucket is
+// empty. If the store fails the request is let through: an outage of a
+// shared store should not take the API down with it.
+func (l *Limiter) Limit(p Policy, keys ...KeyFunc) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !l.Enabled {
+			return ctx.Next()
+		}
+
+		for _, keyFn := range keys {
+			key := keyFn(ctx)
+			if key == "" {
+				continue
+			}
+
+			res, err := l.Store.Take(ctx.UserContext(), p.Name+":"+key, p.Limit)
+			if err != nil {
+				slog.ErrorContext(ctx.UserContext(), "rate limit store failed", "policy", p.Name, "error", err)
+				continue
+			}
+
+			if tighter(ctx, res) {
+				setHeaders(ctx, p, res)
+			}
+
+			if !res.Allowed {
+				metrics.RateLimited.WithLabelValues(p.Name).Inc()
+				ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				return rejected(ctx)
+			}
+		}
+
+		return ctx.Next()
+	}
+}
+
+func tighter(ctx *fiber.Ctx, res Result) bool {
+	prev, ok := ctx.Locals(localsKey).(Result)
+	if ok && prev.Remaining <= res.Remaining && res.Allowed {
+		return false
+	}
+	ctx.Locals(localsKey, res)
+	return true
+}
+
+// setHeaders writes the RateLimit-* fields of the IETF httpapi draft.
+func setHeaders(ctx *fiber.Ctx, p Policy, res Result) {
+	ctx.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	ctx.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	ctx.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	ctx.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit.Requests, ceilSeconds(p.Limit.Window)))
+}
+
+// rejected writes the same body shape as rest.ErrorResponse; rest builds on
+// this package, so it cannot be imported here.
+func rejected(ctx *fiber.Ctx) error {
+	err := domain.ErrTooManyRequests
+	return ctx.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"code":    err.Code,
+		"message": err.Message,
+	})
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ByIP counts requests per client address.
+func ByIP(ctx *fiber.Ctx) string {
+	return "ip:" + ctx.IP()
+}
+
+// ByUser counts requests per authenticated user. It must run after the auth
+// middleware.
+func ByUser(ctx *fiber.Ctx) string {
+	user, ok := ctx.Locals("user").(domain.User)
+	if !ok || user.ID == 0 {
+		return ""
+	}
+	return "user:" + strconv.FormatUint(uint64(user.ID), 10)
+}
+
+// ByPhone counts requests per phone number given in the JSON body, as rules
+// normalize it, so neither rotating IPs nor writing one number in another
+// way gets around the limit. Numbers rules reject are counted by their
+// digits.
+func ByPhone(rules helper.PhoneRules) KeyFunc {
+	return func(ctx *fiber.Ctx) string {
+		var body struct {
+			Phone string `json:"phone"`
+		}
+		if err := json.Unmarshal(ctx.Body(), &body); err != nil {
+			return ""
+		}
+
+		if phone, err := rules.Normalize(body.Phone); err == nil {
+			return "phone:" + phone
+		}
+
+		phone := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, body.Phone)
+
+		if phone == "" {
+			return ""
+		}
+		return "phone:" + phone
+	}
+}
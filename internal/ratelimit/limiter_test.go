@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// failingStore is a store that is down.
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("store is down")
+}
+
+func serve(t *testing.T, handler fiber.Handler, body string) *http.Response {
+	t.Helper()
+
+	app := fiber.New()
+	app.Post("/", handler, func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestLimitRejectsPastTheBurst(t *testing.T) {
+	l := &Limiter{Store: NewMemoryStore(), Enabled: true}
+	handler := l.Limit(Policy{"test", Limit{Requests: 2, Window: time.Hour}}, ByIP)
+
+	for i := range 2 {
+		if res := serve(t, handler, ""); res.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d = %d, want 200", i+1, res.StatusCode)
+		}
+	}
+
+	res := serve(t, handler, "")
+	if res.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("request past the burst = %d, want 429", res.StatusCode)
+	}
+	if got := res.Header.Get(fiber.HeaderRetryAfter); got != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", got)
+	}
+	if got := res.Header.Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+}
+
+func TestLimitFailsOpen(t *testing.T) {
+	l := &Limiter{Store: failingStore{}, Enabled: true}
+	handler := l.Limit(Policy{"test", Limit{Requests: 1, Window: time.Hour}}, ByIP)
+
+	for i := range 3 {
+		if res := serve(t, handler, ""); res.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d with the store down = %d, want 200", i+1, res.StatusCode)
+		}
+	}
+}
+
+func TestLimitDisabled(t *testing.T) {
+	l := &Limiter{Store: NewMemoryStore()}
+	handler := l.Limit(Policy{"test", Limit{Requests: 1, Window: time.Hour}}, ByIP)
+
+	for i := range 3 {
+		if res := serve(t, handler, ""); res.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d with limits disabled = %d, want 200", i+1, res.StatusCode)
+		}
+	}
+}
+
+func TestByPhone(t *testing.T) {
+	key := ByPhone(helper.NewPhoneRules(config.SmsConfig{DefaultCountry: "PT", AllowedCountries: []string{"PT"}}))
+
+	tests := []struct {
+		body, want string
+	}{
+		{`{"phone": "912345678"}`, "phone:+351912345678"},
+		{`{"phone": "+351 912 345 678"}`, "phone:+351912345678"},
+		{`{"phone": "00351912345678"}`, "phone:+351912345678"},
+		{`{"phone": "+1 555 0100"}`, "phone:15550100"},
+		{`{"phone": ""}`, ""},
+		{`{}`, ""},
+		{`not json`, ""},
+	}
+
+	for _, tt := range tests {
+		var got string
+		serve(t, func(ctx *fiber.Ctx) error {
+			got = key(ctx)
+			return ctx.Next()
+		}, tt.body)
+
+		if got != tt.want {
+			t.Errorf("ByPhone(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestLimitByPhoneSharesBucketAcrossFormats(t *testing.T) {
+	rules := helper.NewPhoneRules(config.SmsConfig{DefaultCountry: "PT", AllowedCountries: []string{"PT"}})
+	l := &Limiter{Store: NewMemoryStore(), Enabled: true}
+	handler := l.Limit(Policy{"sms", Limit{Requests: 2, Window: time.Hour}}, ByPhone(rules))
+
+	serve(t, handler, `{"phone": "912345678"}`)
+	serve(t, handler, `{"phone": "+351 912 345 678"}`)
+
+	if res := serve(t, handler, `{"phone": "00351912345678"}`); res.StatusCode != fiber.StatusTooManyRequests {
+		t.Errorf("third request for the same number = %d, want 429", res.StatusCode)
+	}
+}
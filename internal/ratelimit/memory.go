@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops buckets that have
+// refilled completely.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	bucket
+	limit Limit
+}
+
+// MemoryStore keeps the buckets in process. Limits are per instance, so with
+// several replicas the effective limit is multiplied by their number.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*memoryEntry{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		s.sweep(now)
+	}
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &memoryEntry{}
+		s.buckets[key] = e
+	}
+	e.limit = limit
+
+	return e.take(limit, now), nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.buckets {
+		if e.full(e.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is a token bucket holding up to Requests tokens, refilled at
+// Requests per Window. Each request takes one token.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// perSecond is the refill rate in tokens per second.
+func (l Limit) perSecond() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// Result describes the bucket after a Take.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, set when not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. Take must be atomic per key; a store shared by
+// several instances of the server (e.g. backed by Redis) makes them enforce
+// one limit between them instead of one each.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is the state of one key, shared by the store implementations.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b up to now and takes a token if one is available.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := limit.perSecond()
+
+	if b.updated.IsZero() {
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.updated = now
+
+	res := Result{Limit: limit.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = seconds((capacity - b.tokens) / rate)
+
+	return res
+}
+
+// full reports whether the bucket has refilled completely by now, after
+// which it is indistinguishable from a new one and can be dropped.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.perSecond() >= float64(limit.Requests)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a MemoryStore clock the tests move by hand.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.Now
+	return s, c
+}
+
+func TestMemoryStoreBurst(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Requests: 3, Window: time.Minute}
+
+	for i := range 3 {
+		res, _ := s.Take(context.Background(), "k", limit)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d = %+v, want allowed with %d remaining", i+1, res, 2-i)
+		}
+	}
+
+	res, _ := s.Take(context.Background(), "k", limit)
+	if res.Allowed || res.Remaining != 0 {
+		t.Fatalf("take past the burst = %+v, want rejected", res)
+	}
+	// one token comes back every 20s
+	if res.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %v, want 20s", res.RetryAfter)
+	}
+	if res.Reset != time.Minute {
+		t.Errorf("Reset = %v, want 1m", res.Reset)
+	}
+
+	// other keys have their own bucket
+	if res, _ := s.Take(context.Background(), "other", limit); !res.Allowed {
+		t.Errorf("take on another key = %+v, want allowed", res)
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Requests: 3, Window: time.Minute}
+
+	for range 3 {
+		s.Take(context.Background(), "k", limit)
+	}
+
+	c.now = c.now.Add(19 * time.Second)
+	if res, _ := s.Take(context.Background(), "k", limit); res.Allowed {
+		t.Fatalf("take before a token refilled = %+v, want rejected", res)
+	}
+
+	c.now = c.now.Add(time.Second)
+	if res, _ := s.Take(context.Background(), "k", limit); !res.Allowed {
+		t.Fatalf("take after a token refilled = %+v, want allowed", res)
+	}
+
+	// a whole window refills the bucket, but never past its size
+	c.now = c.now.Add(10 * time.Minute)
+	for i := range 3 {
+		if res, _ := s.Take(context.Background(), "k", limit); !res.Allowed {
+			t.Fatalf("take %d after a window = %+v, want allowed", i+1, res)
+		}
+	}
+	if res, _ := s.Take(context.Background(), "k", limit); res.Allowed {
+		t.Fatalf("take past a refilled bucket = %+v, want rejected", res)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	s.Take(context.Background(), "full", limit)
+	s.Take(context.Background(), "full", limit)
+
+	c.now = c.now.Add(2 * time.Minute)
+	s.Take(context.Background(), "new", limit)
+
+	if _, ok := s.buckets["full"]; ok {
+		t.Error("a refilled bucket was kept after a sweep")
+	}
+	if _, ok := s.buckets["new"]; !ok {
+		t.Error("a bucket in use was swept")
+	}
+}
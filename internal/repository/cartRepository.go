@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type CartRepository interface {
+	FindCartItems(ctx context.Context, userId uint) ([]*domain.CartItem, error)
+	// SaveCartItem adds the product or variant to the cart or replaces its
+	// quantity.
+	SaveCartItem(ctx context.Context, item *domain.CartItem) error
+	DeleteCartItem(ctx context.Context, userId uint, productId uint, variantId *uint) error
+	ClearCart(ctx context.Context, userId uint) error
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+func (r cartRepository) FindCartItems(ctx context.Context, userId uint) ([]*domain.CartItem, error) {
+	var items []*domain.CartItem
+
+	err := r.db.WithContext(ctx).Preload("Product").Preload("Product.Options", orderByPosition).Preload("Variant").Where("user_id = ?", userId).Order("id").Find(&items).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find cart items")
+	}
+
+	return items, nil
+}
+
+func (r cartRepository) SaveCartItem(ctx context.Context, item *domain.CartItem) error {
+	// one partial unique index covers plain products and another variants
+	conflict := clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "variant_id IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"qty", "updated_at"}),
+	}
+
+	if item.VariantID != nil {
+		conflict.Columns = []clause.Column{{Name: "user_id"}, {Name: "variant_id"}}
+		conflict.TargetWhere = clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "variant_id IS NOT NULL"}}}
+	}
+
+	err := r.db.WithContext(ctx).Omit("Product", "Variant").Clauses(conflict).Create(item).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to save cart item")
+	}
+
+	return nil
+}
+
+func (r cartRepository) DeleteCartItem(ctx context.Context, userId uint, productId uint, variantId *uint) error {
+	query := r.db.WithContext(ctx).Where("user_id = ? AND product_id = ?", userId, productId)
+	if variantId != nil {
+		query = query.Where("variant_id = ?", *variantId)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+
+	err := query.Delete(&domain.CartItem{}).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to delete cart item")
+	}
+
+	return nil
+}
+
+func (r cartRepository) ClearCart(ctx context.Context, userId uint) error {
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&domain.CartItem{}).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to clear cart")
+	}
+
+	return nil
+}
@@ -1,18 +1,53 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"go-ecommerce-app/internal/domain"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 )
 
 type CatalogRepository interface {
-	CreateCategory(c *domain.Category) error
-	FindCategories() ([]*domain.Category, error)
-	FindCategoryById(id int) (*domain.Category, error)
-	EditCategory(c *domain.Category) (*domain.Category, error)
-	DeleteCategory(id int) error
+	CreateCategory(ctx context.Context, c *domain.Category) error
+	FindCategories(ctx context.Context) ([]*domain.Category, error)
+	FindCategoryById(ctx context.Context, id int) (*domain.Category, error)
+	EditCategory(ctx context.Context, c *domain.Category) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+	FindCategoryByName(ctx context.Context, name string) (*domain.Category, error)
+	SetCategoryTaxClass(ctx context.Context, id uint, taxClass string) error
+
+	CreateProduct(ctx context.Context, e *domain.Product) error
+	FindProducts(ctx context.Context) ([]*domain.Product, error)
+	// FindProductById loads the product with its options, variants and images.
+	FindProductById(ctx context.Context, id uint) (*domain.Product, error)
+	FindSellerProducts(ctx context.Context, sellerId uint) ([]*domain.Product, error)
+	UpdateProductDimensions(ctx context.Context, e *domain.Product) error
+
+	// SaveProductOptions replaces the option types of a product.
+	SaveProductOptions(ctx context.Context, productId uint, options []domain.ProductOption) error
+	CreateVariant(ctx context.Context, v *domain.ProductVariant) error
+	FindVariantById(ctx context.Context, id uint, productId uint) (*domain.ProductVariant, error)
+	UpdateVariant(ctx context.Context, v *domain.ProductVariant) (*domain.ProductVariant, error)
+	// DeleteVariant deletes the variant unless unpaid orders hold its stock.
+	DeleteVariant(ctx context.Context, id uint, productId uint) error
+
+	// Stock changes are single conditional updates, so concurrent checkouts
+	// can't oversell: the one that would take the stock below zero matches no
+	// row. They apply to the variant when variantId is set and to the
+	// product otherwise. The bool reports whether the change was applied.
+	ReserveStock(ctx context.Context, productId uint, variantId *uint, qty uint) (bool, error)
+	ReleaseStock(ctx context.Context, productId uint, variantId *uint, qty uint) error
+	CommitStock(ctx context.Context, productId uint, variantId *uint, qty uint) error
+	TakeStock(ctx context.Context, productId uint, variantId *uint, qty uint) (bool, error)
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func orderById(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
 }
 
 func NewCatalogRepository(db *gorm.DB) CatalogRepository {
@@ -23,21 +58,21 @@ type catalogRepository struct {
 	db *gorm.DB
 }
 
-func (c catalogRepository) CreateCategory(e *domain.Category) error {
-	err := c.db.Create(e).Error
+func (c catalogRepository) CreateCategory(ctx context.Context, e *domain.Category) error {
+	err := c.db.WithContext(ctx).Create(e).Error
 
 	if err != nil {
-		log.Printf("db_error: %v\n", err)
+		slog.ErrorContext(ctx, "db error", "error", err)
 		return errors.New("failed to create category")
 	}
 
 	return nil
 }
 
-func (c catalogRepository) FindCategories() ([]*domain.Category, error) {
+func (c catalogRepository) FindCategories(ctx context.Context) ([]*domain.Category, error) {
 	var categories []*domain.Category
 
-	err := c.db.Find(&categories).Error
+	err := c.db.WithContext(ctx).Find(&categories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,37 +80,307 @@ func (c catalogRepository) FindCategories() ([]*domain.Category, error) {
 	return categories, nil
 }
 
-func (c catalogRepository) FindCategoryById(id int) (*domain.Category, error) {
+func (c catalogRepository) FindCategoryById(ctx context.Context, id int) (*domain.Category, error) {
 	var category domain.Category
 
-	err := c.db.First(&category, id).Error
+	err := c.db.WithContext(ctx).First(&category, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrCategoryNotFound
+	}
 
 	if err != nil {
-		log.Printf("db_error: %v\n", err)
+		slog.ErrorContext(ctx, "db error", "error", err)
 		return nil, errors.New("failed to find category")
 	}
 
 	return &category, nil
 }
 
-func (c catalogRepository) EditCategory(e *domain.Category) (*domain.Category, error) {
-	err := c.db.Save(&e).Error
+func (c catalogRepository) EditCategory(ctx context.Context, e *domain.Category) (*domain.Category, error) {
+	err := c.db.WithContext(ctx).Save(&e).Error
 
 	if err != nil {
-		log.Printf("db_error: %v\n", err)
+		slog.ErrorContext(ctx, "db error", "error", err)
 		return nil, errors.New("failed to edit category")
 	}
 
 	return e, nil
 }
 
-func (c catalogRepository) DeleteCategory(id int) error {
-	err := c.db.Delete(&domain.Category{}, id).Error
+func (c catalogRepository) DeleteCategory(ctx context.Context, id int) error {
+	err := c.db.WithContext(ctx).Delete(&domain.Category{}, id).Error
 
 	if err != nil {
-		log.Printf("db_error: %v\n", err)
+		slog.ErrorContext(ctx, "db error", "error", err)
 		return errors.New("failed to delete category")
 	}
 
 	return nil
 }
+
+func (c catalogRepository) SetCategoryTaxClass(ctx context.Context, id uint, taxClass string) error {
+	res := c.db.WithContext(ctx).Model(&domain.Category{}).Where("id = ?", id).Update("tax_class", taxClass)
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to update category")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+func (c catalogRepository) FindCategoryByName(ctx context.Context, name string) (*domain.Category, error) {
+	var category domain.Category
+
+	err := c.db.WithContext(ctx).First(&category, "name = ?", name).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find category")
+	}
+
+	return &category, nil
+}
+
+func (c catalogRepository) CreateProduct(ctx context.Context, e *domain.Product) error {
+	err := c.db.WithContext(ctx).Create(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create product")
+	}
+
+	return nil
+}
+
+func (c catalogRepository) FindProducts(ctx context.Context) ([]*domain.Product, error) {
+	var products []*domain.Product
+
+	err := c.db.WithContext(ctx).Preload("Options", orderByPosition).Preload("Variants", "active = ?", true).Preload("Images", orderByPosition).Order("id").Find(&products).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find products")
+	}
+
+	return products, nil
+}
+
+func (c catalogRepository) FindSellerProducts(ctx context.Context, sellerId uint) ([]*domain.Product, error) {
+	var products []*domain.Product
+
+	err := c.db.WithContext(ctx).Preload("Options", orderByPosition).Preload("Variants", orderById).Preload("Images", orderByPosition).Where("user_id = ?", sellerId).Order("id").Find(&products).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find products")
+	}
+
+	return products, nil
+}
+
+func (c catalogRepository) FindProductById(ctx context.Context, id uint) (*domain.Product, error) {
+	var product domain.Product
+
+	err := c.db.WithContext(ctx).Preload("Options", orderByPosition).Preload("Variants", orderById).Preload("Images", orderByPosition).First(&product, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrProductNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find product")
+	}
+
+	return &product, nil
+}
+
+func (c catalogRepository) UpdateProductDimensions(ctx context.Context, e *domain.Product) error {
+	err := c.db.WithContext(ctx).Model(e).Select("Weight", "Length", "Width", "Height").Updates(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update product")
+	}
+
+	return nil
+}
+
+func (c catalogRepository) SaveProductOptions(ctx context.Context, productId uint, options []domain.ProductOption) error {
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productId).Delete(&domain.ProductOption{}).Error; err != nil {
+			return err
+		}
+
+		if len(options) == 0 {
+			return nil
+		}
+
+		return tx.Create(&options).Error
+	})
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to save product options")
+	}
+
+	return nil
+}
+
+func (c catalogRepository) CreateVariant(ctx context.Context, v *domain.ProductVariant) error {
+	err := c.db.WithContext(ctx).Create(v).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrVariantExists
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create variant")
+	}
+
+	return nil
+}
+
+func (c catalogRepository) FindVariantById(ctx context.Context, id uint, productId uint) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+
+	err := c.db.WithContext(ctx).Where("product_id = ?", productId).First(&variant, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrVariantNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find variant")
+	}
+
+	return &variant, nil
+}
+
+func (c catalogRepository) UpdateVariant(ctx context.Context, v *domain.ProductVariant) (*domain.ProductVariant, error) {
+	// reserved is only changed by the stock methods
+	err := c.db.WithContext(ctx).Omit("Reserved", "CreatedAt").Save(v).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, domain.ErrVariantExists
+	}
+
+	if errors.Is(err, gorm.ErrCheckConstraintViolated) {
+		return nil, domain.ErrStockBelowReserved
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to update variant")
+	}
+
+	return v, nil
+}
+
+func (c catalogRepository) DeleteVariant(ctx context.Context, id uint, productId uint) error {
+	res := c.db.WithContext(ctx).Where("id = ? AND product_id = ? AND reserved = 0", id, productId).Delete(&domain.ProductVariant{})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete variant")
+	}
+
+	if res.RowsAffected == 0 {
+		if _, err := c.FindVariantById(ctx, id, productId); err != nil {
+			return err
+		}
+
+		return domain.ErrVariantReserved
+	}
+
+	return nil
+}
+
+// stockRow scopes an update to the row holding the stock: the variant when
+// there is one, the product otherwise.
+func (c catalogRepository) stockRow(ctx context.Context, productId uint, variantId *uint) *gorm.DB {
+	if variantId != nil {
+		return c.db.WithContext(ctx).Model(&domain.ProductVariant{}).Where("id = ? AND product_id = ?", *variantId, productId)
+	}
+
+	return c.db.WithContext(ctx).Model(&domain.Product{}).Where("id = ?", productId)
+}
+
+// ReserveStock holds qty units for an unpaid order if that many are
+// available.
+func (c catalogRepository) ReserveStock(ctx context.Context, productId uint, variantId *uint, qty uint) (bool, error) {
+	res := c.stockRow(ctx, productId, variantId).
+		Where("stock - reserved >= ?", qty).
+		UpdateColumn("reserved", gorm.Expr("reserved + ?", qty))
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to reserve stock")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ReleaseStock gives back units held by ReserveStock.
+func (c catalogRepository) ReleaseStock(ctx context.Context, productId uint, variantId *uint, qty uint) error {
+	res := c.stockRow(ctx, productId, variantId).
+		Where("reserved >= ?", qty).
+		UpdateColumn("reserved", gorm.Expr("reserved - ?", qty))
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to release stock")
+	}
+
+	if res.RowsAffected == 0 {
+		slog.WarnContext(ctx, "released more stock than reserved", "product_id", productId, "variant_id", variantId, "qty", qty)
+	}
+
+	return nil
+}
+
+// CommitStock turns units held by ReserveStock into a sale.
+func (c catalogRepository) CommitStock(ctx context.Context, productId uint, variantId *uint, qty uint) error {
+	res := c.stockRow(ctx, productId, variantId).
+		Where("reserved >= ?", qty).
+		UpdateColumns(map[string]interface{}{
+			"stock":    gorm.Expr("stock - ?", qty),
+			"reserved": gorm.Expr("reserved - ?", qty),
+		})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to commit stock")
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.New("failed to commit stock: reservation not held")
+	}
+
+	return nil
+}
+
+// TakeStock sells qty units without a prior reservation, if that many are
+// available.
+func (c catalogRepository) TakeStock(ctx context.Context, productId uint, variantId *uint, qty uint) (bool, error) {
+	res := c.stockRow(ctx, productId, variantId).
+		Where("stock - reserved >= ?", qty).
+		UpdateColumn("stock", gorm.Expr("stock - ?", qty))
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to take stock")
+	}
+
+	return res.RowsAffected == 1, nil
+}
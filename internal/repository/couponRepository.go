@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository interface {
+	FindCoupons(ctx context.Context) ([]*domain.Coupon, error)
+	FindCouponById(ctx context.Context, id uint) (*domain.Coupon, error)
+	FindCouponByCode(ctx context.Context, code string) (*domain.Coupon, error)
+	CreateCoupon(ctx context.Context, c *domain.Coupon) error
+	UpdateCoupon(ctx context.Context, c *domain.Coupon) error
+	DeleteCoupon(ctx context.Context, id uint) error
+
+	// RedeemCoupon counts a use of the coupon and reports whether it did;
+	// it refuses once the usage limit is reached. The coupon's row stays
+	// locked until the transaction ends, so redemptions of the same coupon
+	// are checked one after the other.
+	RedeemCoupon(ctx context.Context, id uint) (bool, error)
+	CreateRedemption(ctx context.Context, r *domain.CouponRedemption) error
+	CountRedemptions(ctx context.Context, couponId uint, userId uint) (int64, error)
+	// ReleaseRedemption gives back the use of a coupon by the order, if it
+	// has one.
+	ReleaseRedemption(ctx context.Context, orderId uint) error
+
+	// FindCartCoupon returns the coupon applied to the user's cart, or nil.
+	FindCartCoupon(ctx context.Context, userId uint) (*domain.Coupon, error)
+	SaveCartCoupon(ctx context.Context, userId uint, couponId uint) error
+	DeleteCartCoupon(ctx context.Context, userId uint) error
+}
+
+// liveCoupons leaves out deleted coupons.
+func liveCoupons(db *gorm.DB) *gorm.DB {
+	return db.Where("coupons.deleted_at IS NULL")
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func (r couponRepository) FindCoupons(ctx context.Context) ([]*domain.Coupon, error) {
+	var coupons []*domain.Coupon
+
+	err := r.db.WithContext(ctx).Scopes(liveCoupons).Order("id").Find(&coupons).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find coupons")
+	}
+
+	return coupons, nil
+}
+
+func (r couponRepository) FindCouponById(ctx context.Context, id uint) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+
+	err := r.db.WithContext(ctx).Scopes(liveCoupons).First(&coupon, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrCouponNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find coupon")
+	}
+
+	return &coupon, nil
+}
+
+func (r couponRepository) FindCouponByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+
+	err := r.db.WithContext(ctx).Scopes(liveCoupons).First(&coupon, "code = ?", code).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrCouponNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find coupon")
+	}
+
+	return &coupon, nil
+}
+
+func (r couponRepository) CreateCoupon(ctx context.Context, c *domain.Coupon) error {
+	err := r.db.WithContext(ctx).Create(c).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrCouponExists
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create coupon")
+	}
+
+	return nil
+}
+
+// UpdateCoupon saves the coupon's settings; Used is only changed by
+// redemptions.
+func (r couponRepository) UpdateCoupon(ctx context.Context, c *domain.Coupon) error {
+	err := r.db.WithContext(ctx).Omit("Used", "CreatedAt", "DeletedAt").Save(c).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrCouponExists
+	}
+
+	// a redemption raised Used over the new limit since the coupon was read
+	if errors.Is(err, gorm.ErrCheckConstraintViolated) {
+		return domain.ErrCouponBelowUsed
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update coupon")
+	}
+
+	return nil
+}
+
+// DeleteCoupon marks the coupon deleted, keeping its redemptions, and takes
+// it off carts.
+func (r couponRepository) DeleteCoupon(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Coupon{}).Scopes(liveCoupons).
+			Where("id = ?", id).
+			UpdateColumn("deleted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrCouponNotFound
+		}
+
+		return tx.Where("coupon_id = ?", id).Delete(&domain.CartCoupon{}).Error
+	})
+
+	if errors.Is(err, domain.ErrCouponNotFound) {
+		return err
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to delete coupon")
+	}
+
+	return nil
+}
+
+func (r couponRepository) RedeemCoupon(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Coupon{}).
+		Scopes(liveCoupons).
+		Where("id = ? AND (usage_limit IS NULL OR used < usage_limit)", id).
+		UpdateColumn("used", gorm.Expr("used + 1"))
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to redeem coupon")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r couponRepository) CreateRedemption(ctx context.Context, red *domain.CouponRedemption) error {
+	err := r.db.WithContext(ctx).Create(red).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to redeem coupon")
+	}
+
+	return nil
+}
+
+func (r couponRepository) CountRedemptions(ctx context.Context, couponId uint, userId uint) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&domain.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponId, userId).
+		Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return 0, errors.New("failed to count coupon redemptions")
+	}
+
+	return count, nil
+}
+
+func (r couponRepository) ReleaseRedemption(ctx context.Context, orderId uint) error {
+	var redemptions []domain.CouponRedemption
+
+	err := r.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("order_id = ?", orderId).
+		Delete(&redemptions).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to release coupon")
+	}
+
+	for _, red := range redemptions {
+		err := r.db.WithContext(ctx).Model(&domain.Coupon{}).
+			Where("id = ? AND used > 0", red.CouponID).
+			UpdateColumn("used", gorm.Expr("used - 1")).Error
+		if err != nil {
+			slog.ErrorContext(ctx, "db error", "error", err)
+			return errors.New("failed to release coupon")
+		}
+	}
+
+	return nil
+}
+
+func (r couponRepository) FindCartCoupon(ctx context.Context, userId uint) (*domain.Coupon, error) {
+	var cartCoupon domain.CartCoupon
+
+	err := r.db.WithContext(ctx).Preload("Coupon", liveCoupons).First(&cartCoupon, "user_id = ?", userId).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find cart coupon")
+	}
+
+	// the coupon was deleted
+	if cartCoupon.Coupon.ID == 0 {
+		return nil, nil
+	}
+
+	return &cartCoupon.Coupon, nil
+}
+
+func (r couponRepository) SaveCartCoupon(ctx context.Context, userId uint, couponId uint) error {
+	err := r.db.WithContext(ctx).Omit("Coupon").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"coupon_id", "created_at"}),
+	}).Create(&domain.CartCoupon{UserID: userId, CouponID: couponId}).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to apply coupon")
+	}
+
+	return nil
+}
+
+func (r couponRepository) DeleteCartCoupon(ctx context.Context, userId uint) error {
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&domain.CartCoupon{}).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to remove coupon")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CurrencyRepository interface {
+	FindRates(ctx context.Context) ([]*domain.CurrencyRate, error)
+	// SaveRates inserts the rates or updates those that exist.
+	SaveRates(ctx context.Context, rates []*domain.CurrencyRate) error
+	DeleteRate(ctx context.Context, currency string) error
+}
+
+func NewCurrencyRepository(db *gorm.DB) CurrencyRepository {
+	return &currencyRepository{db: db}
+}
+
+type currencyRepository struct {
+	db *gorm.DB
+}
+
+func (r currencyRepository) FindRates(ctx context.Context) ([]*domain.CurrencyRate, error) {
+	var rates []*domain.CurrencyRate
+
+	err := r.db.WithContext(ctx).Order("currency").Find(&rates).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find currency rates")
+	}
+
+	return rates, nil
+}
+
+func (r currencyRepository) SaveRates(ctx context.Context, rates []*domain.CurrencyRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rates).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to save currency rates")
+	}
+
+	return nil
+}
+
+func (r currencyRepository) DeleteRate(ctx context.Context, currency string) error {
+	res := r.db.WithContext(ctx).Where("currency = ?", currency).Delete(&domain.CurrencyRate{})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete currency rate")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrRateNotFound
+	}
+
+	return nil
+}
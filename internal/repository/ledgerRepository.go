@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/pkg/money"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository interface {
+	// CreateEntries records the entries, skipping any an order already has
+	// for the same seller and type, so replayed payment events are harmless.
+	CreateEntries(ctx context.Context, entries []*domain.LedgerEntry) error
+	FindEntries(ctx context.Context, sellerId uint, limit int) ([]*domain.LedgerEntry, error)
+	// Balances sums the entries of a seller per currency.
+	Balances(ctx context.Context, sellerId uint) ([]money.Money, error)
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func (r ledgerRepository) CreateEntries(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(entries).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create ledger entries")
+	}
+
+	return nil
+}
+
+func (r ledgerRepository) FindEntries(ctx context.Context, sellerId uint, limit int) ([]*domain.LedgerEntry, error) {
+	var entries []*domain.LedgerEntry
+
+	err := r.db.WithContext(ctx).Where("seller_id = ?", sellerId).Order("id desc").Limit(limit).Find(&entries).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find ledger entries")
+	}
+
+	return entries, nil
+}
+
+func (r ledgerRepository) Balances(ctx context.Context, sellerId uint) ([]money.Money, error) {
+	var balances []money.Money
+
+	err := r.db.WithContext(ctx).Model(&domain.LedgerEntry{}).
+		Select("SUM(amount)::BIGINT AS amount, currency").
+		Where("seller_id = ?", sellerId).
+		Group("currency").Order("currency").
+		Scan(&balances).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to sum ledger entries")
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+type MediaRepository interface {
+	CreateImages(ctx context.Context, images []*domain.ProductImage) error
+	// FindImages returns the gallery of a product in display order.
+	FindImages(ctx context.Context, productId uint) ([]*domain.ProductImage, error)
+	FindImageById(ctx context.Context, id uint, productId uint) (*domain.ProductImage, error)
+	DeleteImage(ctx context.Context, id uint, productId uint) error
+	// SetImagePositions orders the gallery as ids lists it.
+	SetImagePositions(ctx context.Context, productId uint, ids []uint) error
+	// SetCoverImage sets the image url shown for the product in listings.
+	SetCoverImage(ctx context.Context, productId uint, url string) error
+	// FindStoredKeys returns which of keys belong to an image or thumbnail.
+	FindStoredKeys(ctx context.Context, keys []string) (map[string]bool, error)
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func (r mediaRepository) CreateImages(ctx context.Context, images []*domain.ProductImage) error {
+	err := r.db.WithContext(ctx).Create(images).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create product images")
+	}
+
+	return nil
+}
+
+func (r mediaRepository) FindImages(ctx context.Context, productId uint) ([]*domain.ProductImage, error) {
+	var images []*domain.ProductImage
+
+	err := r.db.WithContext(ctx).Where("product_id = ?", productId).Order("position, id").Find(&images).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find product images")
+	}
+
+	return images, nil
+}
+
+func (r mediaRepository) FindImageById(ctx context.Context, id uint, productId uint) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+
+	err := r.db.WithContext(ctx).First(&image, "id = ? AND product_id = ?", id, productId).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrImageNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find product image")
+	}
+
+	return &image, nil
+}
+
+func (r mediaRepository) DeleteImage(ctx context.Context, id uint, productId uint) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productId).Delete(&domain.ProductImage{})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete product image")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrImageNotFound
+	}
+
+	return nil
+}
+
+func (r mediaRepository) SetImagePositions(ctx context.Context, productId uint, ids []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			err := tx.Model(&domain.ProductImage{}).Where("id = ? AND product_id = ?", id, productId).
+				UpdateColumn("position", i).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to order product images")
+	}
+
+	return nil
+}
+
+func (r mediaRepository) SetCoverImage(ctx context.Context, productId uint, url string) error {
+	err := r.db.WithContext(ctx).Model(&domain.Product{}).Where("id = ?", productId).
+		UpdateColumn("image_url", url).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update product image")
+	}
+
+	return nil
+}
+
+func (r mediaRepository) FindStoredKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	var found []string
+
+	err := r.db.WithContext(ctx).Raw(
+		"SELECT key FROM product_images WHERE key IN ? UNION SELECT thumbnail_key FROM product_images WHERE thumbnail_key IN ?",
+		keys, keys,
+	).Scan(&found).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find stored media")
+	}
+
+	res := make(map[string]bool, len(found))
+	for _, k := range found {
+		res[k] = true
+	}
+
+	return res, nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type OrderRepository interface {
+	CreateOrder(ctx context.Context, o *domain.Order) error
+	FindOrders(ctx context.Context, userId uint) ([]*domain.Order, error)
+	FindOrderById(ctx context.Context, id uint, userId uint) (*domain.Order, error)
+	FindOrderByReference(ctx context.Context, reference string) (*domain.Order, error)
+	// FindSellerOrders returns the orders in status that have items of the
+	// seller, newest first.
+	FindSellerOrders(ctx context.Context, sellerId uint, status string) ([]*domain.Order, error)
+	FindSellerOrderById(ctx context.Context, id uint, sellerId uint) (*domain.Order, error)
+	FindExpiredOrders(ctx context.Context, now time.Time, limit int) ([]*domain.Order, error)
+	SetPaymentId(ctx context.Context, id uint, paymentId string) error
+	// UpdateOrderStatus moves the order to status if it currently has one of
+	// the from statuses, and reports whether it did. Concurrent transitions
+	// (a payment arriving while the sweeper expires the order) are decided
+	// by whichever update reaches the row first.
+	UpdateOrderStatus(ctx context.Context, id uint, status string, from ...string) (bool, error)
+
+	CreateReservation(ctx context.Context, r *domain.StockReservation) error
+	FindReservations(ctx context.Context, orderId uint, status string) ([]*domain.StockReservation, error)
+	UpdateReservationStatus(ctx context.Context, id uint, status string, from string) (bool, error)
+
+	// ShipItem adds qty to the shipped units of an order item and reports
+	// whether it did; it refuses to ship more units than were ordered.
+	ShipItem(ctx context.Context, id uint, orderId uint, qty uint) (bool, error)
+	CreateShipment(ctx context.Context, s *domain.Shipment) error
+	// LockFulfilment locks the fulfilment's row until the transaction ends,
+	// so shipments of the same fulfilment are recorded one after the other.
+	LockFulfilment(ctx context.Context, id uint) error
+	FindSellerItems(ctx context.Context, orderId uint, sellerId uint) ([]domain.OrderItem, error)
+	UpdateFulfilmentStatus(ctx context.Context, id uint, status string) error
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{db: db}
+}
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// withFulfilments preloads the items of an order and what has been shipped.
+func withFulfilments(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", orderById).
+		Preload("Fulfilments", orderById).
+		Preload("Fulfilments.Shipments", orderById).
+		Preload("Fulfilments.Shipments.Items", orderById)
+}
+
+func (r orderRepository) CreateOrder(ctx context.Context, o *domain.Order) error {
+	err := r.db.WithContext(ctx).Create(o).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create order")
+	}
+
+	return nil
+}
+
+func (r orderRepository) FindOrders(ctx context.Context, userId uint) ([]*domain.Order, error) {
+	var orders []*domain.Order
+
+	err := r.db.WithContext(ctx).Scopes(withFulfilments).Where("user_id = ?", userId).Order("id desc").Find(&orders).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find orders")
+	}
+
+	return orders, nil
+}
+
+func (r orderRepository) FindOrderById(ctx context.Context, id uint, userId uint) (*domain.Order, error) {
+	var order domain.Order
+
+	err := r.db.WithContext(ctx).Scopes(withFulfilments).Where("user_id = ?", userId).First(&order, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find order")
+	}
+
+	return &order, nil
+}
+
+func (r orderRepository) FindOrderByReference(ctx context.Context, reference string) (*domain.Order, error) {
+	var order domain.Order
+
+	err := r.db.WithContext(ctx).Preload("Items").First(&order, "reference = ?", reference).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find order")
+	}
+
+	return &order, nil
+}
+
+func (r orderRepository) FindSellerOrders(ctx context.Context, sellerId uint, status string) ([]*domain.Order, error) {
+	var orders []*domain.Order
+
+	err := r.db.WithContext(ctx).Scopes(withFulfilments).
+		Where("status = ? AND id IN (?)", status, r.db.Model(&domain.Fulfilment{}).Select("order_id").Where("seller_id = ?", sellerId)).
+		Order("id desc").Find(&orders).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find orders")
+	}
+
+	return orders, nil
+}
+
+func (r orderRepository) FindSellerOrderById(ctx context.Context, id uint, sellerId uint) (*domain.Order, error) {
+	var order domain.Order
+
+	err := r.db.WithContext(ctx).Scopes(withFulfilments).
+		Where("id IN (?)", r.db.Model(&domain.Fulfilment{}).Select("order_id").Where("seller_id = ?", sellerId)).
+		First(&order, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find order")
+	}
+
+	return &order, nil
+}
+
+func (r orderRepository) FindExpiredOrders(ctx context.Context, now time.Time, limit int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+
+	err := r.db.WithContext(ctx).Preload("Items").
+		Where("status = ? AND expires_at <= ?", domain.OrderPendingPayment, now).
+		Order("expires_at").Limit(limit).Find(&orders).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find expired orders")
+	}
+
+	return orders, nil
+}
+
+func (r orderRepository) SetPaymentId(ctx context.Context, id uint, paymentId string) error {
+	err := r.db.WithContext(ctx).Model(&domain.Order{ID: id}).Update("payment_id", paymentId).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update order")
+	}
+
+	return nil
+}
+
+func (r orderRepository) UpdateOrderStatus(ctx context.Context, id uint, status string, from ...string) (bool, error) {
+	updates := map[string]interface{}{"status": status, "updated_at": time.Now()}
+	if status == domain.OrderPaid {
+		updates["paid_at"] = time.Now()
+	}
+
+	res := r.db.WithContext(ctx).Model(&domain.Order{}).
+		Where("id = ? AND status IN ?", id, from).
+		UpdateColumns(updates)
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to update order status")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r orderRepository) CreateReservation(ctx context.Context, res *domain.StockReservation) error {
+	err := r.db.WithContext(ctx).Create(res).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create stock reservation")
+	}
+
+	return nil
+}
+
+func (r orderRepository) FindReservations(ctx context.Context, orderId uint, status string) ([]*domain.StockReservation, error) {
+	var reservations []*domain.StockReservation
+
+	err := r.db.WithContext(ctx).Where("order_id = ? AND status = ?", orderId, status).Order("product_id").Find(&reservations).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find stock reservations")
+	}
+
+	return reservations, nil
+}
+
+func (r orderRepository) UpdateReservationStatus(ctx context.Context, id uint, status string, from string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.StockReservation{}).
+		Where("id = ? AND status = ?", id, from).
+		UpdateColumns(map[string]interface{}{"status": status, "updated_at": time.Now()})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to update stock reservation")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r orderRepository) ShipItem(ctx context.Context, id uint, orderId uint, qty uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.OrderItem{}).
+		Where("id = ? AND order_id = ? AND shipped_qty + ? <= qty", id, orderId, qty).
+		UpdateColumn("shipped_qty", gorm.Expr("shipped_qty + ?", qty))
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return false, errors.New("failed to ship order item")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r orderRepository) CreateShipment(ctx context.Context, s *domain.Shipment) error {
+	err := r.db.WithContext(ctx).Create(s).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create shipment")
+	}
+
+	return nil
+}
+
+func (r orderRepository) LockFulfilment(ctx context.Context, id uint) error {
+	var fulfilment domain.Fulfilment
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&fulfilment, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrFulfilmentNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to lock fulfilment")
+	}
+
+	return nil
+}
+
+func (r orderRepository) FindSellerItems(ctx context.Context, orderId uint, sellerId uint) ([]domain.OrderItem, error) {
+	var items []domain.OrderItem
+
+	err := r.db.WithContext(ctx).Scopes(orderById).
+		Where("order_id = ? AND seller_id = ?", orderId, sellerId).
+		Find(&items).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find order items")
+	}
+
+	return items, nil
+}
+
+func (r orderRepository) UpdateFulfilmentStatus(ctx context.Context, id uint, status string) error {
+	res := r.db.WithContext(ctx).Model(&domain.Fulfilment{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"status": status, "updated_at": time.Now()})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to update fulfilment")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrFulfilmentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+type ShippingRepository interface {
+	// FindZones loads every zone with its methods.
+	FindZones(ctx context.Context) ([]*domain.ShippingZone, error)
+	FindZoneById(ctx context.Context, id uint) (*domain.ShippingZone, error)
+	CreateZone(ctx context.Context, e *domain.ShippingZone) error
+	UpdateZone(ctx context.Context, e *domain.ShippingZone) error
+	// DeleteZone deletes the zone and its methods.
+	DeleteZone(ctx context.Context, id uint) error
+
+	FindMethodById(ctx context.Context, id uint) (*domain.ShippingMethod, error)
+	CreateMethod(ctx context.Context, e *domain.ShippingMethod) error
+	UpdateMethod(ctx context.Context, e *domain.ShippingMethod) error
+	DeleteMethod(ctx context.Context, id uint) error
+}
+
+func NewShippingRepository(db *gorm.DB) ShippingRepository {
+	return &shippingRepository{db: db}
+}
+
+type shippingRepository struct {
+	db *gorm.DB
+}
+
+func (r shippingRepository) FindZones(ctx context.Context) ([]*domain.ShippingZone, error) {
+	var zones []*domain.ShippingZone
+
+	err := r.db.WithContext(ctx).Preload("Methods", orderById).Order("id").Find(&zones).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find shipping zones")
+	}
+
+	return zones, nil
+}
+
+func (r shippingRepository) FindZoneById(ctx context.Context, id uint) (*domain.ShippingZone, error) {
+	var zone domain.ShippingZone
+
+	err := r.db.WithContext(ctx).Preload("Methods", orderById).First(&zone, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrZoneNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find shipping zone")
+	}
+
+	return &zone, nil
+}
+
+func (r shippingRepository) CreateZone(ctx context.Context, e *domain.ShippingZone) error {
+	err := r.db.WithContext(ctx).Omit("Methods").Create(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create shipping zone")
+	}
+
+	return nil
+}
+
+func (r shippingRepository) UpdateZone(ctx context.Context, e *domain.ShippingZone) error {
+	err := r.db.WithContext(ctx).Omit("Methods", "CreatedAt").Save(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update shipping zone")
+	}
+
+	return nil
+}
+
+func (r shippingRepository) DeleteZone(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&domain.ShippingZone{}, id)
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete shipping zone")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrZoneNotFound
+	}
+
+	return nil
+}
+
+func (r shippingRepository) FindMethodById(ctx context.Context, id uint) (*domain.ShippingMethod, error) {
+	var method domain.ShippingMethod
+
+	err := r.db.WithContext(ctx).First(&method, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMethodNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find shipping method")
+	}
+
+	return &method, nil
+}
+
+func (r shippingRepository) CreateMethod(ctx context.Context, e *domain.ShippingMethod) error {
+	err := r.db.WithContext(ctx).Create(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create shipping method")
+	}
+
+	return nil
+}
+
+func (r shippingRepository) UpdateMethod(ctx context.Context, e *domain.ShippingMethod) error {
+	err := r.db.WithContext(ctx).Omit("CreatedAt").Save(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update shipping method")
+	}
+
+	return nil
+}
+
+func (r shippingRepository) DeleteMethod(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&domain.ShippingMethod{}, id)
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete shipping method")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrMethodNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"gorm.io/gorm"
+	"log/slog"
+	"time"
+)
+
+type SmsRepository interface {
+	CreateMessage(ctx context.Context, m *domain.SmsMessage) error
+	UpdateMessage(ctx context.Context, m *domain.SmsMessage) error
+	// CountMessages counts reserved and sent messages created after since,
+	// to phone or, when phone is empty, to anyone.
+	CountMessages(ctx context.Context, phone string, since time.Time) (int64, error)
+}
+
+func NewSmsRepository(db *gorm.DB) SmsRepository {
+	return &smsRepository{db: db}
+}
+
+type smsRepository struct {
+	db *gorm.DB
+}
+
+func (r smsRepository) CreateMessage(ctx context.Context, m *domain.SmsMessage) error {
+	err := r.db.WithContext(ctx).Create(m).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create sms message")
+	}
+
+	return nil
+}
+
+func (r smsRepository) UpdateMessage(ctx context.Context, m *domain.SmsMessage) error {
+	err := r.db.WithContext(ctx).Model(m).Select("status", "error", "updated_at").Updates(m).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update sms message")
+	}
+
+	return nil
+}
+
+func (r smsRepository) CountMessages(ctx context.Context, phone string, since time.Time) (int64, error) {
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&domain.SmsMessage{}).
+		Where("status IN ? AND created_at > ?", []string{domain.SmsReserved, domain.SmsSent}, since)
+	if phone != "" {
+		query = query.Where("phone = ?", phone)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return 0, errors.New("failed to count sms messages")
+	}
+
+	return count, nil
+}
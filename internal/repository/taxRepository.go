@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	// FindTaxRates lists the rates of a country, or of every country when
+	// country is empty.
+	FindTaxRates(ctx context.Context, country string) ([]*domain.TaxRate, error)
+	FindTaxRateById(ctx context.Context, id uint) (*domain.TaxRate, error)
+	CreateTaxRate(ctx context.Context, e *domain.TaxRate) error
+	UpdateTaxRate(ctx context.Context, e *domain.TaxRate) error
+	DeleteTaxRate(ctx context.Context, id uint) error
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func (r taxRepository) FindTaxRates(ctx context.Context, country string) ([]*domain.TaxRate, error) {
+	var rates []*domain.TaxRate
+
+	query := r.db.WithContext(ctx)
+	if country != "" {
+		query = query.Where("country = ?", country)
+	}
+
+	err := query.Order("country, region, tax_class").Find(&rates).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find tax rates")
+	}
+
+	return rates, nil
+}
+
+func (r taxRepository) FindTaxRateById(ctx context.Context, id uint) (*domain.TaxRate, error) {
+	var rate domain.TaxRate
+
+	err := r.db.WithContext(ctx).First(&rate, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrTaxRateNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find tax rate")
+	}
+
+	return &rate, nil
+}
+
+func (r taxRepository) CreateTaxRate(ctx context.Context, e *domain.TaxRate) error {
+	err := r.db.WithContext(ctx).Create(e).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrTaxRateExists
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create tax rate")
+	}
+
+	return nil
+}
+
+func (r taxRepository) UpdateTaxRate(ctx context.Context, e *domain.TaxRate) error {
+	err := r.db.WithContext(ctx).Save(e).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrTaxRateExists
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update tax rate")
+	}
+
+	return nil
+}
+
+func (r taxRepository) DeleteTaxRate(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&domain.TaxRate{}, id)
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to delete tax rate")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrTaxRateNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories groups the repositories bound to the same database handle.
+// Inside UnitOfWork.Do they all share one transaction.
+type Repositories struct {
+	User     UserRepository
+	Catalog  CatalogRepository
+	Webhook  WebhookRepository
+	Sms      SmsRepository
+	Cart     CartRepository
+	Order    OrderRepository
+	Media    MediaRepository
+	Ledger   LedgerRepository
+	Shipping ShippingRepository
+	Coupon   CouponRepository
+}
+
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		User:     NewUserRepository(db),
+		Catalog:  NewCatalogRepository(db),
+		Webhook:  NewWebhookRepository(db),
+		Sms:      NewSmsRepository(db),
+		Cart:     NewCartRepository(db),
+		Order:    NewOrderRepository(db),
+		Media:    NewMediaRepository(db),
+		Ledger:   NewLedgerRepository(db),
+		Shipping: NewShippingRepository(db),
+		Coupon:   NewCouponRepository(db),
+	}
+}
+
+// UnitOfWork runs several repository calls atomically. If fn returns an
+// error, or panics, everything it did is rolled back.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func (u unitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
@@ -1,21 +1,29 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"go-ecommerce-app/internal/domain"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"log"
+	"log/slog"
 )
 
 type UserRepository interface {
-	CreateUser(usr domain.User) (domain.User, error)
-	FindUser(email string) (domain.User, error)
-	FindUserById(id uint) (domain.User, error)
-	UpdateUser(id uint, usr domain.User) (domain.User, error)
-	GetVerificationCode(email string) (int, error)
-
-	CreateBankAccount(e domain.BankAccount) error
+	CreateUser(ctx context.Context, usr domain.User) (domain.User, error)
+	FindUser(ctx context.Context, email string) (domain.User, error)
+	FindUserById(ctx context.Context, id uint) (domain.User, error)
+	UpdateUser(ctx context.Context, id uint, usr domain.User) (domain.User, error)
+	GetVerificationCode(ctx context.Context, email string) (int, error)
+
+	CreateBankAccount(ctx context.Context, e domain.BankAccount) error
+
+	FindUserWithAddresses(ctx context.Context, id uint) (domain.User, error)
+	CreateAddress(ctx context.Context, e *domain.Address) error
+	FindAddresses(ctx context.Context, userId uint) ([]domain.Address, error)
+	FindAddressById(ctx context.Context, id uint, userId uint) (*domain.Address, error)
+	UpdateAddress(ctx context.Context, e *domain.Address) error
+	DeleteAddress(ctx context.Context, id uint, userId uint) error
 }
 
 type userRepository struct {
@@ -26,73 +34,222 @@ func NewUserRepository(db *gorm.DB) *userRepository {
 	return &userRepository{db: db}
 }
 
-func (r userRepository) CreateUser(usr domain.User) (domain.User, error) {
-	err := r.db.Create(&usr).Error
+func (r userRepository) CreateUser(ctx context.Context, usr domain.User) (domain.User, error) {
+	err := r.db.WithContext(ctx).Create(&usr).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.User{}, domain.ErrEmailTaken
+	}
 
 	if err != nil {
-		log.Printf("database error while creating user: %v\n", err)
+		slog.ErrorContext(ctx, "db error while creating user", "error", err)
 		return domain.User{}, errors.New("failed to create user")
 	}
 
 	return usr, nil
 }
 
-func (r userRepository) FindUser(email string) (domain.User, error) {
+func (r userRepository) FindUser(ctx context.Context, email string) (domain.User, error) {
 	var user domain.User
 
-	err := r.db.First(&user, "email = ?", email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+
 	if err != nil {
-		log.Printf("database error while finding user: %v\n", err)
-		return domain.User{}, errors.New("user does not exist")
+		slog.ErrorContext(ctx, "db error while finding user", "error", err)
+		return domain.User{}, errors.New("failed to find user")
 	}
 
 	return user, nil
 }
 
-func (r userRepository) FindUserById(id uint) (domain.User, error) {
+func (r userRepository) FindUserById(ctx context.Context, id uint) (domain.User, error) {
 	var user domain.User
 
-	err := r.db.First(&user, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
 
 	if err != nil {
-		log.Printf("database error while finding user: %v\n", err)
-		return domain.User{}, errors.New("user does not exist")
+		slog.ErrorContext(ctx, "db error while finding user", "error", err)
+		return domain.User{}, errors.New("failed to find user")
 	}
 
 	return user, nil
 }
 
-func (r userRepository) UpdateUser(id uint, usr domain.User) (domain.User, error) {
+func (r userRepository) UpdateUser(ctx context.Context, id uint, usr domain.User) (domain.User, error) {
 	var user domain.User
 
-	err := r.db.Model(&user).Clauses(clause.Returning{}).Where("id = ?", id).Updates(usr).Error
+	err := r.db.WithContext(ctx).Model(&user).Clauses(clause.Returning{}).Where("id = ?", id).Updates(usr).Error
 
 	if err != nil {
-		log.Printf("database error while updating user: %v\n", err)
+		slog.ErrorContext(ctx, "db error while updating user", "error", err)
 		return domain.User{}, errors.New("cannot update user")
 	}
 
 	return user, nil
 }
 
-func (r userRepository) GetVerificationCode(email string) (int, error) {
+func (r userRepository) GetVerificationCode(ctx context.Context, email string) (int, error) {
 	var user domain.User
 
-	err := r.db.First(&user, "email = ?", email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, domain.ErrUserNotFound
+	}
 
 	if err != nil {
-		log.Printf("database error while finding user: %v\n", err)
-		return 0, errors.New("user does not exist")
+		slog.ErrorContext(ctx, "db error while finding user", "error", err)
+		return 0, errors.New("failed to find user")
 	}
 
 	if user.Verified {
-		log.Printf("user is verified\n")
+		slog.DebugContext(ctx, "user is verified")
 		return user.Code, nil
 	}
 
 	return 1245, nil
 }
 
-func (r userRepository) CreateBankAccount(e domain.BankAccount) error {
-	return r.db.Create(&e).Error
+func (r userRepository) CreateBankAccount(ctx context.Context, e domain.BankAccount) error {
+	err := r.db.WithContext(ctx).Create(&e).Error
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrBankAccountTaken
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while creating bank account", "error", err)
+		return errors.New("failed to create bank account")
+	}
+
+	return nil
+}
+
+func (r userRepository) FindUserWithAddresses(ctx context.Context, id uint) (domain.User, error) {
+	var user domain.User
+
+	err := r.db.WithContext(ctx).Preload("Addresses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&user, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while finding user", "error", err)
+		return domain.User{}, errors.New("failed to find user")
+	}
+
+	return user, nil
+}
+
+// clearDefaultAddresses unsets the default flags that e claims on every other
+// address of the same user, so a user has at most one default of each kind.
+func clearDefaultAddresses(tx *gorm.DB, e *domain.Address) error {
+	if e.DefaultShipping {
+		err := tx.Model(&domain.Address{}).
+			Where("user_id = ? AND id <> ?", e.UserID, e.ID).
+			Update("default_shipping", false).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.DefaultBilling {
+		err := tx.Model(&domain.Address{}).
+			Where("user_id = ? AND id <> ?", e.UserID, e.ID).
+			Update("default_billing", false).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r userRepository) CreateAddress(ctx context.Context, e *domain.Address) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(e).Error; err != nil {
+			return err
+		}
+
+		return clearDefaultAddresses(tx, e)
+	})
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while creating address", "error", err)
+		return errors.New("failed to create address")
+	}
+
+	return nil
+}
+
+func (r userRepository) FindAddresses(ctx context.Context, userId uint) ([]domain.Address, error) {
+	var addresses []domain.Address
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("id").Find(&addresses).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while finding addresses", "error", err)
+		return nil, errors.New("failed to find addresses")
+	}
+
+	return addresses, nil
+}
+
+func (r userRepository) FindAddressById(ctx context.Context, id uint, userId uint) (*domain.Address, error) {
+	var address domain.Address
+
+	err := r.db.WithContext(ctx).First(&address, "id = ? AND user_id = ?", id, userId).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAddressNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while finding address", "error", err)
+		return nil, errors.New("failed to find address")
+	}
+
+	return &address, nil
+}
+
+func (r userRepository) UpdateAddress(ctx context.Context, e *domain.Address) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(e).Error; err != nil {
+			return err
+		}
+
+		return clearDefaultAddresses(tx, e)
+	})
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error while updating address", "error", err)
+		return errors.New("failed to update address")
+	}
+
+	return nil
+}
+
+func (r userRepository) DeleteAddress(ctx context.Context, id uint, userId uint) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userId).Delete(&domain.Address{})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error while deleting address", "error", res.Error)
+		return errors.New("failed to delete address")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrAddressNotFound
+	}
+
+	return nil
 }
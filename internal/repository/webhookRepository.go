@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, e *domain.WebhookEndpoint) error
+	FindEndpoints(ctx context.Context, userId uint) ([]*domain.WebhookEndpoint, error)
+	FindEndpoint(ctx context.Context, id uint) (*domain.WebhookEndpoint, error)
+	FindEndpointById(ctx context.Context, id uint, userId uint) (*domain.WebhookEndpoint, error)
+	FindSubscribedEndpoints(ctx context.Context, userId uint, event string) ([]*domain.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, e *domain.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, id uint, userId uint) error
+
+	CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, endpointId uint) ([]*domain.WebhookDelivery, error)
+	FindDeliveryById(ctx context.Context, id uint, endpointId uint) (*domain.WebhookDelivery, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now
+	// and moves their next attempt lease later, so other workers skip them
+	// while they are sent. A delivery whose worker dies is due again once
+	// the lease runs out.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	// ClaimDelivery makes the delivery pending with its next attempt lease
+	// moved later, unless it is pending and not due yet, as it is while a
+	// worker holds a lease on it. It returns ErrDeliveryBusy then.
+	ClaimDelivery(ctx context.Context, id uint, now time.Time, lease time.Duration) error
+	UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+	CreateAttempt(ctx context.Context, a *domain.WebhookAttempt) error
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func (r webhookRepository) CreateEndpoint(ctx context.Context, e *domain.WebhookEndpoint) error {
+	err := r.db.WithContext(ctx).Create(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create webhook endpoint")
+	}
+
+	return nil
+}
+
+func (r webhookRepository) FindEndpoints(ctx context.Context, userId uint) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("id").Find(&endpoints).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook endpoints")
+	}
+
+	return endpoints, nil
+}
+
+func (r webhookRepository) FindEndpoint(ctx context.Context, id uint) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook endpoint")
+	}
+
+	return &endpoint, nil
+}
+
+func (r webhookRepository) FindEndpointById(ctx context.Context, id uint, userId uint) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).First(&endpoint, "id = ? AND user_id = ?", id, userId).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook endpoint")
+	}
+
+	return &endpoint, nil
+}
+
+func (r webhookRepository) FindSubscribedEndpoints(ctx context.Context, userId uint, event string) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).Where("user_id = ? AND active = ?", userId, true).Find(&endpoints).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook endpoints")
+	}
+
+	subscribed := make([]*domain.WebhookEndpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.Subscribes(event) {
+			subscribed = append(subscribed, e)
+		}
+	}
+
+	return subscribed, nil
+}
+
+func (r webhookRepository) UpdateEndpoint(ctx context.Context, e *domain.WebhookEndpoint) error {
+	err := r.db.WithContext(ctx).Save(e).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update webhook endpoint")
+	}
+
+	return nil
+}
+
+func (r webhookRepository) DeleteEndpoint(ctx context.Context, id uint, userId uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND user_id = ?", id, userId).Delete(&domain.WebhookEndpoint{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrWebhookNotFound
+		}
+
+		deliveries := tx.Model(&domain.WebhookDelivery{}).Select("id").Where("endpoint_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&domain.WebhookAttempt{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("endpoint_id = ?", id).Delete(&domain.WebhookDelivery{}).Error
+	})
+
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		return err
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to delete webhook endpoint")
+	}
+
+	return nil
+}
+
+func (r webhookRepository) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	err := r.db.WithContext(ctx).Create(d).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+func (r webhookRepository) FindDeliveries(ctx context.Context, endpointId uint) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointId).Order("id desc").Limit(100).Find(&deliveries).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+func (r webhookRepository) FindDeliveryById(ctx context.Context, id uint, endpointId uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Preload("DeliveryAttempts").First(&delivery, "id = ? AND endpoint_id = ?", id, endpointId).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to find webhook delivery")
+	}
+
+	return &delivery, nil
+}
+
+func (r webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+
+	// rows another worker is claiming are skipped rather than waited for
+	due := r.db.Model(&domain.WebhookDelivery{}).Select("id").
+		Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("next_attempt_at").Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	err := r.db.WithContext(ctx).Model(&deliveries).Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return nil, errors.New("failed to claim due webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+func (r webhookRepository) ClaimDelivery(ctx context.Context, id uint, now time.Time, lease time.Duration) error {
+	res := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("id = ? AND (status <> ? OR next_attempt_at <= ?)", id, domain.DeliveryPending, now).
+		UpdateColumns(map[string]any{"status": domain.DeliveryPending, "next_attempt_at": now.Add(lease)})
+
+	if res.Error != nil {
+		slog.ErrorContext(ctx, "db error", "error", res.Error)
+		return errors.New("failed to claim webhook delivery")
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrDeliveryBusy
+	}
+
+	return nil
+}
+
+func (r webhookRepository) UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	err := r.db.WithContext(ctx).Omit("DeliveryAttempts").Save(d).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to update webhook delivery")
+	}
+
+	return nil
+}
+
+func (r webhookRepository) CreateAttempt(ctx context.Context, a *domain.WebhookAttempt) error {
+	err := r.db.WithContext(ctx).Create(a).Error
+
+	if err != nil {
+		slog.ErrorContext(ctx, "db error", "error", err)
+		return errors.New("failed to record webhook attempt")
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+)
+
+type CartService struct {
+	Repo     repository.CartRepository
+	Catalog  repository.CatalogRepository
+	Coupons  repository.CouponRepository
+	Currency CurrencyService
+	Auth     helper.Auth
+	Config   config.AppConfig
+}
+
+func (s CartService) GetCart(ctx context.Context, userId uint) ([]*domain.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetCart")
+	defer span.End()
+
+	return s.Repo.FindCartItems(ctx, userId)
+}
+
+// SetItem sets the quantity of a product, or of one of its variants, in the
+// cart, or removes it when the quantity is zero. Products with variants can
+// only be added by variant. Stock is only checked here as a courtesy; it is
+// reserved at checkout.
+func (s CartService) SetItem(ctx context.Context, userId uint, input dto.CartItemInput) ([]*domain.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.SetItem")
+	defer span.End()
+
+	if input.Qty == 0 {
+		if err := s.Repo.DeleteCartItem(ctx, userId, input.ProductId, input.VariantId); err != nil {
+			return nil, err
+		}
+
+		return s.Repo.FindCartItems(ctx, userId)
+	}
+
+	product, err := s.Catalog.FindProductById(ctx, input.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cart total and the order are converted from the product currency
+	rates, err := s.Currency.Rates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rates.Supports(product.Price.Currency) {
+		return nil, domain.ErrCurrencyNotCharged
+	}
+
+	available := product.Available()
+	if product.HasVariants() || input.VariantId != nil {
+		if input.VariantId == nil {
+			return nil, domain.ErrVariantRequired
+		}
+
+		variant, ok := product.Variant(*input.VariantId)
+		if !ok {
+			return nil, domain.ErrVariantNotFound
+		}
+
+		if !variant.Active {
+			return nil, domain.ErrVariantUnavailable
+		}
+
+		available = variant.Available()
+	}
+
+	if available < input.Qty {
+		return nil, domain.ErrOutOfStock
+	}
+
+	err = s.Repo.SaveCartItem(ctx, &domain.CartItem{
+		UserID:    userId,
+		ProductID: input.ProductId,
+		VariantID: input.VariantId,
+		Qty:       input.Qty,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindCartItems(ctx, userId)
+}
+
+// GetCoupon returns the coupon applied to the cart, or nil.
+func (s CartService) GetCoupon(ctx context.Context, userId uint) (*domain.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetCoupon")
+	defer span.End()
+
+	return s.Coupons.FindCartCoupon(ctx, userId)
+}
+
+// ApplyCoupon applies the coupon with the given code to the cart, replacing
+// any other. The coupon must discount the cart as it is now, shown in
+// currency; checkout checks it again when it is redeemed.
+func (s CartService) ApplyCoupon(ctx context.Context, userId uint, input dto.CouponCodeInput, rates money.Rates, currency string) ([]*domain.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.ApplyCoupon")
+	defer span.End()
+
+	coupon, err := s.Coupons.FindCouponByCode(ctx, couponCode(input.Code))
+	if errors.Is(err, domain.ErrCouponNotFound) {
+		return nil, domain.ErrCouponInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.Repo.FindCartItems(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, domain.ErrCartEmpty
+	}
+
+	if _, err := s.Discount(ctx, userId, *coupon, items, rates, currency); err != nil {
+		return nil, err
+	}
+
+	if err := s.Coupons.SaveCartCoupon(ctx, userId, coupon.ID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (s CartService) RemoveCoupon(ctx context.Context, userId uint) ([]*domain.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.RemoveCoupon")
+	defer span.End()
+
+	if err := s.Coupons.DeleteCartCoupon(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindCartItems(ctx, userId)
+}
+
+// Discount works out what coupon takes off the user's cart items, in
+// currency, or tells why it doesn't apply. Shipping isn't chosen until
+// checkout, so a free shipping coupon takes nothing off here.
+func (s CartService) Discount(ctx context.Context, userId uint, coupon domain.Coupon, items []*domain.CartItem, rates money.Rates, currency string) (domain.Discount, error) {
+	ctx, span := tracing.Start(ctx, "CartService.Discount")
+	defer span.End()
+
+	if err := checkCoupon(ctx, s.Coupons, coupon, userId); err != nil {
+		return domain.Discount{}, err
+	}
+
+	return cartDiscount(coupon, items, money.New(0, currency), rates, currency)
+}
@@ -1,13 +1,388 @@
 package service
 
 import (
+	"context"
 	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
 	"go-ecommerce-app/internal/helper"
 	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"regexp"
+	"strings"
 )
 
+// maxMatrixVariants caps how many variants one matrix request may create.
+const maxMatrixVariants = 500
+
+var skuUnsafe = regexp.MustCompile(`[^A-Z0-9]+`)
+
 type CatalogService struct {
 	Repo   repository.CatalogRepository
+	Tx     repository.UnitOfWork
 	Auth   helper.Auth
 	Config config.AppConfig
 }
+
+func (s CatalogService) GetCategories(ctx context.Context) ([]*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetCategories")
+	defer span.End()
+
+	return s.Repo.FindCategories(ctx)
+}
+
+func (s CatalogService) GetCategory(ctx context.Context, id uint) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetCategory")
+	defer span.End()
+
+	return s.Repo.FindCategoryById(ctx, int(id))
+}
+
+func (s CatalogService) GetProducts(ctx context.Context) ([]*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetProducts")
+	defer span.End()
+
+	return s.Repo.FindProducts(ctx)
+}
+
+// GetProduct returns a product as buyers see it, without inactive variants.
+func (s CatalogService) GetProduct(ctx context.Context, id uint) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetProduct")
+	defer span.End()
+
+	product, err := s.Repo.FindProductById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	active := product.Variants[:0]
+	for _, v := range product.Variants {
+		if v.Active {
+			active = append(active, v)
+		}
+	}
+	product.Variants = active
+
+	return product, nil
+}
+
+func (s CatalogService) GetSellerProducts(ctx context.Context, sellerId uint) ([]*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetSellerProducts")
+	defer span.End()
+
+	return s.Repo.FindSellerProducts(ctx, sellerId)
+}
+
+// GetSellerProduct returns a product of the seller with all its variants.
+// Products of other sellers are reported as not found.
+func (s CatalogService) GetSellerProduct(ctx context.Context, id uint, sellerId uint) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GetSellerProduct")
+	defer span.End()
+
+	return s.findSellerProduct(ctx, id, sellerId)
+}
+
+func (s CatalogService) findSellerProduct(ctx context.Context, id uint, sellerId uint) (*domain.Product, error) {
+	product, err := s.Repo.FindProductById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint(product.UserId) != sellerId {
+		return nil, domain.ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+// SetDimensions sets the weight and packed size shipping is quoted with.
+func (s CatalogService) SetDimensions(ctx context.Context, productId uint, sellerId uint, input dto.ProductDimensionsInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.SetDimensions")
+	defer span.End()
+
+	product, err := s.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Weight = input.Weight
+	product.Length = input.Length
+	product.Width = input.Width
+	product.Height = input.Height
+
+	if err := s.Repo.UpdateProductDimensions(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// SetOptions replaces the option types of a product. Options or values that
+// existing variants use can't be removed, and options can't be added while
+// the product has variants, since those would then miss a value.
+func (s CatalogService) SetOptions(ctx context.Context, productId uint, sellerId uint, input dto.ProductOptionsInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.SetOptions")
+	defer span.End()
+
+	product, err := s.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	options := make([]domain.ProductOption, 0, len(input.Options))
+	for i, o := range input.Options {
+		name := strings.ToLower(strings.TrimSpace(o.Name))
+		if names[name] {
+			return nil, domain.Validation("duplicate_product_option", "option names must be unique")
+		}
+		names[name] = true
+
+		options = append(options, domain.ProductOption{
+			ProductID: productId,
+			Name:      strings.TrimSpace(o.Name),
+			Position:  i,
+			Values:    o.Values,
+		})
+	}
+
+	for _, v := range product.Variants {
+		if !variantMatches(v.Options, options) {
+			return nil, domain.ErrOptionsInUse
+		}
+	}
+
+	if err := s.Repo.SaveProductOptions(ctx, productId, options); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindProductById(ctx, productId)
+}
+
+// CreateVariant adds a variant and returns the product with all its
+// variants.
+func (s CatalogService) CreateVariant(ctx context.Context, productId uint, sellerId uint, input dto.VariantInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.CreateVariant")
+	defer span.End()
+
+	product, err := s.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.HasVariants() {
+		return nil, domain.ErrNoProductOptions
+	}
+
+	options, ok := canonicalOptions(input.Options, product.Options)
+	if !ok {
+		return nil, domain.ErrInvalidVariant
+	}
+
+	variant := &domain.ProductVariant{
+		ProductID:  productId,
+		Sku:        strings.TrimSpace(input.Sku),
+		Options:    options,
+		OptionsKey: domain.VariantOptionsKey(options),
+		Price:      input.Price,
+		Stock:      input.Stock,
+		ImageUrl:   input.ImageUrl,
+		Active:     true,
+	}
+
+	if err := s.Repo.CreateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindProductById(ctx, productId)
+}
+
+// GenerateVariants fills in the variant matrix: every combination of option
+// values without a variant gets one, sharing the given price and stock.
+func (s CatalogService) GenerateVariants(ctx context.Context, productId uint, sellerId uint, input dto.VariantMatrixInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.GenerateVariants")
+	defer span.End()
+
+	product, err := s.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.HasVariants() {
+		return nil, domain.ErrNoProductOptions
+	}
+
+	combinations := 1
+	for _, o := range product.Options {
+		combinations *= len(o.Values)
+	}
+
+	if combinations > maxMatrixVariants {
+		return nil, domain.Validation("variant_matrix_too_large", "the options make too many combinations to generate at once")
+	}
+
+	existing := map[string]bool{}
+	for _, v := range product.Variants {
+		existing[v.OptionsKey] = true
+	}
+
+	prefix := skuPart(input.SkuPrefix)
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		for _, options := range optionCombinations(product.Options) {
+			key := domain.VariantOptionsKey(options)
+			if existing[key] {
+				continue
+			}
+
+			sku := prefix
+			for _, o := range product.Options {
+				sku += "-" + skuPart(options[o.Name])
+			}
+
+			err := repos.Catalog.CreateVariant(ctx, &domain.ProductVariant{
+				ProductID:  productId,
+				Sku:        sku,
+				Options:    options,
+				OptionsKey: key,
+				Price:      input.Price,
+				Stock:      input.Stock,
+				Active:     true,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindProductById(ctx, productId)
+}
+
+func (s CatalogService) UpdateVariant(ctx context.Context, productId uint, id uint, sellerId uint, input dto.VariantUpdateInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.UpdateVariant")
+	defer span.End()
+
+	if _, err := s.findSellerProduct(ctx, productId, sellerId); err != nil {
+		return nil, err
+	}
+
+	variant, err := s.Repo.FindVariantById(ctx, id, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Sku != nil {
+		variant.Sku = strings.TrimSpace(*input.Sku)
+	}
+
+	if input.Price != nil {
+		variant.Price = input.Price
+		if *input.Price == 0 {
+			variant.Price = nil
+		}
+	}
+
+	if input.Stock != nil {
+		if *input.Stock < variant.Reserved {
+			return nil, domain.ErrStockBelowReserved
+		}
+		variant.Stock = *input.Stock
+	}
+
+	if input.ImageUrl != nil {
+		variant.ImageUrl = *input.ImageUrl
+	}
+
+	if input.Active != nil {
+		variant.Active = *input.Active
+	}
+
+	if _, err := s.Repo.UpdateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindProductById(ctx, productId)
+}
+
+func (s CatalogService) DeleteVariant(ctx context.Context, productId uint, id uint, sellerId uint) error {
+	ctx, span := tracing.Start(ctx, "CatalogService.DeleteVariant")
+	defer span.End()
+
+	if _, err := s.findSellerProduct(ctx, productId, sellerId); err != nil {
+		return err
+	}
+
+	return s.Repo.DeleteVariant(ctx, id, productId)
+}
+
+// canonicalOptions checks that input sets exactly one listed value for each
+// option, and returns it spelled the way the product lists them.
+func canonicalOptions(input map[string]string, options []domain.ProductOption) (map[string]string, bool) {
+	if len(input) != len(options) {
+		return nil, false
+	}
+
+	res := make(map[string]string, len(options))
+	for _, o := range options {
+		value, found := "", false
+		for name, v := range input {
+			if strings.EqualFold(name, o.Name) {
+				value, found = v, true
+			}
+		}
+
+		if !found {
+			return nil, false
+		}
+
+		listed := false
+		for _, v := range o.Values {
+			if strings.EqualFold(v, strings.TrimSpace(value)) {
+				res[o.Name], listed = v, true
+			}
+		}
+
+		if !listed {
+			return nil, false
+		}
+	}
+
+	return res, true
+}
+
+// variantMatches reports whether a variant's options are still valid for
+// the given option types.
+func variantMatches(variant map[string]string, options []domain.ProductOption) bool {
+	_, ok := canonicalOptions(variant, options)
+	return ok
+}
+
+// optionCombinations lists every combination of the options' values.
+func optionCombinations(options []domain.ProductOption) []map[string]string {
+	res := []map[string]string{{}}
+	for _, o := range options {
+		next := make([]map[string]string, 0, len(res)*len(o.Values))
+		for _, partial := range res {
+			for _, v := range o.Values {
+				combination := make(map[string]string, len(partial)+1)
+				for name, value := range partial {
+					combination[name] = value
+				}
+				combination[o.Name] = v
+				next = append(next, combination)
+			}
+		}
+		res = next
+	}
+
+	return res
+}
+
+func skuPart(s string) string {
+	return strings.Trim(skuUnsafe.ReplaceAllString(strings.ToUpper(s), "-"), "-")
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+	"strings"
+	"time"
+)
+
+type CouponService struct {
+	Repo   repository.CouponRepository
+	Auth   helper.Auth
+	Config config.AppConfig
+}
+
+func (s CouponService) GetCoupons(ctx context.Context) ([]*domain.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.GetCoupons")
+	defer span.End()
+
+	return s.Repo.FindCoupons(ctx)
+}
+
+// CreateCoupon adds a coupon whose amounts are in the store currency.
+func (s CouponService) CreateCoupon(ctx context.Context, input dto.CouponInput) (*domain.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.CreateCoupon")
+	defer span.End()
+
+	coupon := &domain.Coupon{Active: true}
+	if err := s.applyCouponInput(coupon, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.CreateCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// UpdateCoupon changes a coupon. Orders already placed keep their discount,
+// so the usage limit can't go below the uses so far.
+func (s CouponService) UpdateCoupon(ctx context.Context, id uint, input dto.CouponInput) (*domain.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.UpdateCoupon")
+	defer span.End()
+
+	coupon, err := s.Repo.FindCouponById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyCouponInput(coupon, input); err != nil {
+		return nil, err
+	}
+
+	if coupon.UsageLimit != nil && *coupon.UsageLimit < coupon.Used {
+		return nil, domain.ErrCouponBelowUsed
+	}
+
+	if err := s.Repo.UpdateCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// DeleteCoupon withdraws a coupon and takes it off the carts it was applied
+// to. Its redemptions are kept for the orders that used it. The code can be
+// used again for a new coupon, which starts with its own usage counts.
+func (s CouponService) DeleteCoupon(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "CouponService.DeleteCoupon")
+	defer span.End()
+
+	return s.Repo.DeleteCoupon(ctx, id)
+}
+
+func (s CouponService) applyCouponInput(coupon *domain.Coupon, input dto.CouponInput) error {
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return domain.ErrCouponWindow
+	}
+
+	coupon.Code = couponCode(input.Code)
+	coupon.Type = input.Type
+	coupon.Percent = 0
+	coupon.Amount = money.New(0, s.Config.Payments.Currency)
+	switch input.Type {
+	case domain.CouponPercentage:
+		coupon.Percent = input.Percent
+	case domain.CouponFixed:
+		coupon.Amount = money.New(input.Amount, s.Config.Payments.Currency)
+	}
+
+	coupon.MinSubtotal = money.New(input.MinSubtotal, s.Config.Payments.Currency)
+	coupon.CategoryIDs = input.CategoryIds
+	coupon.SellerIDs = input.SellerIds
+	coupon.ProductIDs = input.ProductIds
+	coupon.UsageLimit = input.UsageLimit
+	coupon.PerUserLimit = input.PerUserLimit
+	coupon.StartsAt = input.StartsAt
+	coupon.EndsAt = input.EndsAt
+	if input.Active != nil {
+		coupon.Active = *input.Active
+	}
+
+	return nil
+}
+
+// couponCode is the stored form of a code as typed by a user.
+func couponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// checkCoupon tells why the user can't use coupon now, if they can't.
+func checkCoupon(ctx context.Context, repo repository.CouponRepository, coupon domain.Coupon, userId uint) error {
+	if err := coupon.Usable(time.Now()); err != nil {
+		return err
+	}
+
+	if coupon.PerUserLimit == nil {
+		return nil
+	}
+
+	used, err := repo.CountRedemptions(ctx, coupon.ID, userId)
+	if err != nil {
+		return err
+	}
+
+	if used >= int64(*coupon.PerUserLimit) {
+		return domain.ErrCouponUserLimit
+	}
+
+	return nil
+}
+
+// cartDiscount works out what coupon takes off the cart shipped for
+// shipping, in currency. The coupon's amounts and the item prices are
+// converted the way checkout converts prices, so the cart shows the
+// discount the order gets.
+func cartDiscount(coupon domain.Coupon, cart []*domain.CartItem, shipping money.Money, rates money.Rates, currency string) (domain.Discount, error) {
+	for _, amount := range []*money.Money{&coupon.Amount, &coupon.MinSubtotal} {
+		if amount.Currency == "" {
+			continue
+		}
+
+		converted, err := rates.Convert(*amount, currency)
+		if err != nil {
+			return domain.Discount{}, domain.ErrCouponInvalid.Wrap(err)
+		}
+		*amount = converted
+	}
+
+	lines := make([]domain.DiscountLine, 0, len(cart))
+	for _, item := range cart {
+		price, err := rates.Convert(item.Price(), currency)
+		if err != nil {
+			return domain.Discount{}, domain.ErrCurrencyNotCharged.Wrap(err)
+		}
+
+		lines = append(lines, domain.DiscountLine{
+			ProductID:  item.ProductID,
+			CategoryID: item.Product.CategoryId,
+			SellerID:   uint(item.Product.UserId),
+			Amount:     price.Mul(int64(item.Qty)),
+		})
+	}
+
+	return coupon.Discount(lines, shipping)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+	"strings"
+)
+
+type CurrencyService struct {
+	Repo   repository.CurrencyRepository
+	Auth   helper.Auth
+	Config config.AppConfig
+}
+
+func (s CurrencyService) GetRates(ctx context.Context) ([]*domain.CurrencyRate, error) {
+	ctx, span := tracing.Start(ctx, "CurrencyService.GetRates")
+	defer span.End()
+
+	return s.Repo.FindRates(ctx)
+}
+
+// SetRates adds or updates the given rates and returns all of them.
+func (s CurrencyService) SetRates(ctx context.Context, input dto.CurrencyRatesInput) ([]*domain.CurrencyRate, error) {
+	ctx, span := tracing.Start(ctx, "CurrencyService.SetRates")
+	defer span.End()
+
+	rates := make([]*domain.CurrencyRate, 0, len(input.Rates))
+	for code, rate := range input.Rates {
+		code = strings.ToUpper(strings.TrimSpace(code))
+
+		if !money.Known(code) || code == s.Config.Payments.Currency {
+			return nil, domain.Validation("invalid_currency", code+" is not a currency that can have a rate")
+		}
+
+		if rate.IsZero() {
+			return nil, domain.Validation("invalid_rate", "the rate of "+code+" must be a positive number")
+		}
+
+		rates = append(rates, &domain.CurrencyRate{Currency: code, Rate: rate})
+	}
+
+	if err := s.Repo.SaveRates(ctx, rates); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindRates(ctx)
+}
+
+func (s CurrencyService) DeleteRate(ctx context.Context, currency string) error {
+	ctx, span := tracing.Start(ctx, "CurrencyService.DeleteRate")
+	defer span.End()
+
+	return s.Repo.DeleteRate(ctx, strings.ToUpper(currency))
+}
+
+// Rates loads the current rates for conversions.
+func (s CurrencyService) Rates(ctx context.Context) (money.Rates, error) {
+	rates, err := s.Repo.FindRates(ctx)
+	if err != nil {
+		return money.Rates{}, err
+	}
+
+	byCode := make(map[string]money.Rate, len(rates))
+	for _, r := range rates {
+		byCode[r.Currency] = r.Rate
+	}
+
+	return money.NewRates(s.Config.Payments.Currency, byCode), nil
+}
+
+// DisplayCurrency picks the first of the requested currencies prices can be
+// shown in, along with the rates to convert them. With none requested it is
+// the store currency. Currencies asked for explicitly must be supported;
+// those from a header are preferences and fall back to the store currency.
+func (s CurrencyService) DisplayCurrency(ctx context.Context, requested []string, explicit bool) (money.Rates, string, error) {
+	ctx, span := tracing.Start(ctx, "CurrencyService.DisplayCurrency")
+	defer span.End()
+
+	rates, err := s.Rates(ctx)
+	if err != nil {
+		return money.Rates{}, "", err
+	}
+
+	for _, code := range requested {
+		if code = strings.ToUpper(code); rates.Supports(code) {
+			return rates, code, nil
+		}
+	}
+
+	if explicit && len(requested) > 0 {
+		return money.Rates{}, "", domain.ErrUnknownCurrency
+	}
+
+	return rates, rates.Base, nil
+}
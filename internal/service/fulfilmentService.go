@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"log/slog"
+)
+
+// FulfilmentService lets sellers ship their part of paid orders.
+type FulfilmentService struct {
+	Repo     repository.OrderRepository
+	Tx       repository.UnitOfWork
+	Webhooks WebhookService
+	Auth     helper.Auth
+	Config   config.AppConfig
+}
+
+// GetOrders returns the seller's part of the orders in status.
+func (s FulfilmentService) GetOrders(ctx context.Context, sellerId uint, status string) ([]*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "FulfilmentService.GetOrders")
+	defer span.End()
+
+	orders, err := s.Repo.FindSellerOrders(ctx, sellerId, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, order := range orders {
+		view := order.ForSeller(sellerId)
+		orders[i] = &view
+	}
+
+	return orders, nil
+}
+
+// GetOrder returns the seller's part of an order.
+func (s FulfilmentService) GetOrder(ctx context.Context, id uint, sellerId uint) (*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "FulfilmentService.GetOrder")
+	defer span.End()
+
+	order, err := s.Repo.FindSellerOrderById(ctx, id, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	view := order.ForSeller(sellerId)
+	return &view, nil
+}
+
+func (s FulfilmentService) GetShipments(ctx context.Context, id uint, sellerId uint) ([]domain.Shipment, error) {
+	ctx, span := tracing.Start(ctx, "FulfilmentService.GetShipments")
+	defer span.End()
+
+	order, err := s.GetOrder(ctx, id, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipments []domain.Shipment
+	for _, f := range order.Fulfilments {
+		shipments = append(shipments, f.Shipments...)
+	}
+
+	return shipments, nil
+}
+
+// CreateShipment records a parcel with some or all of the units of the
+// seller's items that haven't shipped yet, and tells the seller's webhooks.
+func (s FulfilmentService) CreateShipment(ctx context.Context, id uint, sellerId uint, input dto.ShipmentInput) (*domain.Shipment, error) {
+	ctx, span := tracing.Start(ctx, "FulfilmentService.CreateShipment")
+	defer span.End()
+
+	order, err := s.GetOrder(ctx, id, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != domain.OrderPaid {
+		return nil, domain.ErrOrderNotPaid
+	}
+
+	if len(order.Fulfilments) == 0 {
+		return nil, domain.ErrFulfilmentNotFound
+	}
+	fulfilment := order.Fulfilments[0]
+
+	lines, err := shipmentLines(order.Items, input.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &domain.Shipment{
+		FulfilmentID:   fulfilment.ID,
+		OrderID:        order.ID,
+		Carrier:        input.Carrier,
+		TrackingNumber: input.TrackingNumber,
+		TrackingUrl:    input.TrackingUrl,
+		Items:          lines,
+	}
+
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		if err := repos.Order.LockFulfilment(ctx, fulfilment.ID); err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			ok, err := repos.Order.ShipItem(ctx, line.OrderItemID, order.ID, line.Qty)
+			if err != nil {
+				return err
+			}
+
+			// another shipment took these units since the order was read
+			if !ok {
+				return domain.ErrInvalidShipment
+			}
+		}
+
+		if err := repos.Order.CreateShipment(ctx, shipment); err != nil {
+			return err
+		}
+
+		// read the items again under the lock, with every shipment so far
+		items, err := repos.Order.FindSellerItems(ctx, order.ID, sellerId)
+		if err != nil {
+			return err
+		}
+
+		return repos.Order.UpdateFulfilmentStatus(ctx, fulfilment.ID, domain.FulfilmentStatus(items))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if order, err = s.GetOrder(ctx, id, sellerId); err == nil {
+		err = s.Webhooks.Publish(ctx, sellerId, domain.EventOrderShipped, dto.NewOrderResponse(*order))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "publish order event failed", "order_id", id, "event", domain.EventOrderShipped, "error", err)
+	}
+
+	return shipment, nil
+}
+
+// shipmentLines picks the units a shipment holds: the requested ones, or
+// every unit not shipped yet when none were requested.
+func shipmentLines(items []domain.OrderItem, requested []dto.ShipmentItemInput) ([]domain.ShipmentItem, error) {
+	var lines []domain.ShipmentItem
+
+	if len(requested) == 0 {
+		for _, item := range items {
+			if item.ShippedQty < item.Qty {
+				lines = append(lines, domain.ShipmentItem{OrderItemID: item.ID, Qty: item.Qty - item.ShippedQty})
+			}
+		}
+
+		if len(lines) == 0 {
+			return nil, domain.ErrNothingToShip
+		}
+		return lines, nil
+	}
+
+	for _, req := range requested {
+		var item *domain.OrderItem
+		for i := range items {
+			if items[i].ID == req.OrderItemId {
+				item = &items[i]
+			}
+		}
+
+		if item == nil || item.ShippedQty+req.Qty > item.Qty {
+			return nil, domain.ErrInvalidShipment
+		}
+
+		lines = append(lines, domain.ShipmentItem{OrderItemID: item.ID, Qty: req.Qty})
+	}
+
+	return lines, nil
+}
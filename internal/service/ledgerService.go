@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+)
+
+// ledgerPageSize is how many recent entries a seller sees.
+const ledgerPageSize = 100
+
+type LedgerService struct {
+	Repo   repository.LedgerRepository
+	Auth   helper.Auth
+	Config config.AppConfig
+}
+
+// GetLedger returns the seller's balance in each currency they were paid in
+// and their most recent entries.
+func (s LedgerService) GetLedger(ctx context.Context, sellerId uint) ([]money.Money, []*domain.LedgerEntry, error) {
+	ctx, span := tracing.Start(ctx, "LedgerService.GetLedger")
+	defer span.End()
+
+	balances, err := s.Repo.Balances(ctx, sellerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := s.Repo.FindEntries(ctx, sellerId, ledgerPageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return balances, entries, nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/storage"
+	"log/slog"
+	"time"
+)
+
+// orphanBatchSize is how many stored keys are looked up in one query.
+const orphanBatchSize = 500
+
+type MediaService struct {
+	Repo    repository.MediaRepository
+	Catalog CatalogService
+	Tx      repository.UnitOfWork
+	Storage storage.Storage
+	Auth    helper.Auth
+	Config  config.AppConfig
+}
+
+// processedImage is an upload that passed validation, with its thumbnail.
+type processedImage struct {
+	image     *domain.ProductImage
+	data      []byte
+	thumbnail []byte
+	thumbType string
+}
+
+// UploadImages validates the uploads, stores them with their thumbnails and
+// appends them to the product's gallery. Nothing is added unless every file
+// is valid. Files are stored before the rows are written, so an image never
+// points at a missing file; files a failed upload couldn't remove are left
+// for CleanupOrphans.
+func (s MediaService) UploadImages(ctx context.Context, productId uint, sellerId uint, uploads []dto.ImageUpload) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "MediaService.UploadImages")
+	defer span.End()
+
+	cfg := s.Config.Media
+
+	if len(uploads) == 0 {
+		return nil, domain.ErrImagesRequired
+	}
+
+	if len(uploads) > cfg.MaxFiles {
+		return nil, domain.Validation("too_many_files", fmt.Sprintf("upload at most %d images at once", cfg.MaxFiles))
+	}
+
+	product, err := s.Catalog.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(product.Images)+len(uploads) > cfg.MaxProductImages {
+		return nil, domain.Conflict("too_many_images", fmt.Sprintf("a product can have at most %d images", cfg.MaxProductImages))
+	}
+
+	position := 0
+	for _, i := range product.Images {
+		position = max(position, i.Position+1)
+	}
+
+	processed := make([]processedImage, 0, len(uploads))
+	for i, upload := range uploads {
+		p, err := s.processImage(productId, upload)
+		if err != nil {
+			return nil, err
+		}
+
+		p.image.Position = position + i
+		processed = append(processed, p)
+	}
+
+	var stored []string
+	for _, p := range processed {
+		if err := s.Storage.Put(ctx, p.image.Key, p.data, p.image.ContentType); err != nil {
+			s.deleteFiles(ctx, stored...)
+			return nil, domain.ErrStorageUnavailable.Wrap(err)
+		}
+		stored = append(stored, p.image.Key)
+
+		if err := s.Storage.Put(ctx, p.image.ThumbnailKey, p.thumbnail, p.thumbType); err != nil {
+			s.deleteFiles(ctx, stored...)
+			return nil, domain.ErrStorageUnavailable.Wrap(err)
+		}
+		stored = append(stored, p.image.ThumbnailKey)
+	}
+
+	images := make([]*domain.ProductImage, 0, len(processed))
+	for _, p := range processed {
+		images = append(images, p.image)
+	}
+
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		if err := repos.Media.CreateImages(ctx, images); err != nil {
+			return err
+		}
+
+		if len(product.Images) == 0 {
+			return repos.Media.SetCoverImage(ctx, productId, images[0].Url)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		s.deleteFiles(ctx, stored...)
+		return nil, err
+	}
+
+	return s.Catalog.Repo.FindProductById(ctx, productId)
+}
+
+// processImage checks the type, size and dimensions of an upload and
+// renders its thumbnail.
+func (s MediaService) processImage(productId uint, upload dto.ImageUpload) (processedImage, error) {
+	cfg := s.Config.Media
+
+	if len(upload.Data) > cfg.MaxFileSize {
+		return processedImage{}, domain.Validation("image_too_large",
+			fmt.Sprintf("%s is larger than %d KiB", upload.Filename, cfg.MaxFileSize/1024))
+	}
+
+	// dimensions come from the header, so huge images are rejected before
+	// their pixels are decoded
+	format, width, height, err := helper.ImageConfig(upload.Data)
+	if err != nil {
+		return processedImage{}, domain.ErrUnsupportedImage.Wrap(err)
+	}
+
+	if min(width, height) < cfg.MinDimension || max(width, height) > cfg.MaxDimension {
+		return processedImage{}, domain.Validation("invalid_image_dimensions",
+			fmt.Sprintf("%s must be between %d and %d pixels on each side", upload.Filename, cfg.MinDimension, cfg.MaxDimension))
+	}
+
+	thumbnail, thumbType, thumbExt, err := helper.Thumbnail(upload.Data, cfg.ThumbnailSize)
+	if err != nil {
+		return processedImage{}, domain.ErrUnsupportedImage.Wrap(err)
+	}
+
+	name, err := helper.RandomString(16)
+	if err != nil {
+		return processedImage{}, err
+	}
+
+	ext := format
+	if ext == "jpeg" {
+		ext = "jpg"
+	}
+
+	key := fmt.Sprintf("products/%d/%s.%s", productId, name, ext)
+	thumbKey := fmt.Sprintf("products/%d/%s_thumb.%s", productId, name, thumbExt)
+
+	return processedImage{
+		image: &domain.ProductImage{
+			ProductID:    productId,
+			Key:          key,
+			ThumbnailKey: thumbKey,
+			Url:          s.Storage.URL(key),
+			ThumbnailUrl: s.Storage.URL(thumbKey),
+			ContentType:  helper.ImageTypes[format],
+			Size:         int64(len(upload.Data)),
+			Width:        width,
+			Height:       height,
+		},
+		data:      upload.Data,
+		thumbnail: thumbnail,
+		thumbType: thumbType,
+	}, nil
+}
+
+// ReorderImages puts the gallery in the order of ids, which must list every
+// image of the product once. The first image becomes the cover.
+func (s MediaService) ReorderImages(ctx context.Context, productId uint, sellerId uint, input dto.ImageOrderInput) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "MediaService.ReorderImages")
+	defer span.End()
+
+	product, err := s.Catalog.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[uint]string, len(product.Images))
+	for _, i := range product.Images {
+		urls[i.ID] = i.Url
+	}
+
+	if len(input.ImageIds) != len(urls) {
+		return nil, domain.ErrInvalidImageOrder
+	}
+
+	for _, id := range input.ImageIds {
+		if _, ok := urls[id]; !ok {
+			return nil, domain.ErrInvalidImageOrder
+		}
+	}
+
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		if err := repos.Media.SetImagePositions(ctx, productId, input.ImageIds); err != nil {
+			return err
+		}
+
+		return repos.Media.SetCoverImage(ctx, productId, urls[input.ImageIds[0]])
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Catalog.Repo.FindProductById(ctx, productId)
+}
+
+// DeleteImage removes an image from the gallery and storage. If it was the
+// cover, the next image takes its place.
+func (s MediaService) DeleteImage(ctx context.Context, productId uint, id uint, sellerId uint) (*domain.Product, error) {
+	ctx, span := tracing.Start(ctx, "MediaService.DeleteImage")
+	defer span.End()
+
+	product, err := s.Catalog.findSellerProduct(ctx, productId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := s.Repo.FindImageById(ctx, id, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		if err := repos.Media.DeleteImage(ctx, id, productId); err != nil {
+			return err
+		}
+
+		if product.ImageUrl != image.Url {
+			return nil
+		}
+
+		cover := ""
+		for _, i := range product.Images {
+			if i.ID != id {
+				cover = i.Url
+				break
+			}
+		}
+
+		return repos.Media.SetCoverImage(ctx, productId, cover)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the row is gone, so files left behind are picked up as orphans
+	s.deleteFiles(ctx, image.Key, image.ThumbnailKey)
+
+	return s.Catalog.Repo.FindProductById(ctx, productId)
+}
+
+// CleanupOrphans deletes stored product files no image refers to, such as
+// leftovers of failed uploads or of deleted products. Files younger than
+// OrphanAge are kept, since an upload may not have written its rows yet.
+func (s MediaService) CleanupOrphans(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "MediaService.CleanupOrphans")
+	defer span.End()
+
+	objects, err := s.Storage.List(ctx, "products/")
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-s.Config.Media.OrphanAge)
+
+	var candidates []string
+	for _, o := range objects {
+		if o.ModTime.Before(cutoff) {
+			candidates = append(candidates, o.Key)
+		}
+	}
+
+	deleted := 0
+	for start := 0; start < len(candidates); start += orphanBatchSize {
+		batch := candidates[start:min(start+orphanBatchSize, len(candidates))]
+
+		known, err := s.Repo.FindStoredKeys(ctx, batch)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, key := range batch {
+			if known[key] {
+				continue
+			}
+
+			if err := s.Storage.Delete(ctx, key); err != nil {
+				slog.ErrorContext(ctx, "delete orphaned media failed", "key", key, "error", err)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		slog.InfoContext(ctx, "orphaned media deleted", "count", deleted)
+	}
+
+	return deleted, nil
+}
+
+// RunCleanup deletes orphaned media every CleanupInterval until ctx is
+// cancelled.
+func (s MediaService) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Media.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CleanupOrphans(ctx); err != nil {
+				slog.ErrorContext(ctx, "media cleanup failed", "error", err)
+			}
+		}
+	}
+}
+
+// deleteFiles removes stored files on a best effort basis; whatever fails
+// is left for CleanupOrphans.
+func (s MediaService) deleteFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "delete media failed", "key", key, "error", err)
+		}
+	}
+}
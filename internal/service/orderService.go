@@ -0,0 +1,761 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/metrics"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+	"go-ecommerce-app/pkg/payments"
+	"go-ecommerce-app/pkg/tax"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const expiredOrdersBatchSize = 100
+
+// errStockGone and errCouponGone abort the transaction that marks a late
+// payment as paid when the stock it needs was sold, or its coupon used up,
+// after the order expired.
+var (
+	errStockGone  = errors.New("stock no longer available")
+	errCouponGone = errors.New("coupon no longer available")
+)
+
+type OrderService struct {
+	Repo     repository.OrderRepository
+	Tx       repository.UnitOfWork
+	Payments payments.Provider
+	Webhooks WebhookService
+	Currency CurrencyService
+	Tax      tax.Calculator
+	Auth     helper.Auth
+	Config   config.AppConfig
+}
+
+// Checkout turns the cart into an order awaiting payment. The stock of every
+// item is reserved in the same transaction, so either the whole cart is
+// held for Checkout.ReservationTTL or nothing is and the cart is left as it
+// was. The payment is created once the reservation is committed.
+//
+// The order is charged in currency, an empty one meaning the store currency.
+// The rate to it is locked on the order and item prices are converted at
+// checkout, so later rate changes don't affect it. Tax and shipping are
+// worked out for the user's default shipping address, which is required,
+// and the chosen shipping method must be one of the cart's options.
+//
+// A coupon applied to the cart is redeemed with the order. Its discount is
+// split across the items it covers before tax, so each seller is credited
+// what the buyer paid for their items.
+func (s OrderService) Checkout(ctx context.Context, userId uint, currency string, input dto.CheckoutInput) (*domain.Order, *payments.Payment, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.Checkout")
+	defer span.End()
+
+	if s.Payments == nil {
+		return nil, nil, domain.ErrPaymentsDisabled
+	}
+
+	rates, err := s.Currency.Rates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if currency == "" {
+		currency = rates.Base
+	}
+
+	rate, ok := rates.Rate(currency)
+	if !ok {
+		return nil, nil, domain.ErrUnknownCurrency
+	}
+
+	reference, err := helper.RandomString(12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := &domain.Order{
+		UserID:       userId,
+		Reference:    "ord_" + reference,
+		Status:       domain.OrderPendingPayment,
+		Amount:       money.New(0, currency),
+		BaseCurrency: rates.Base,
+		ExchangeRate: rate,
+		Discount:     money.New(0, currency),
+		ExpiresAt:    time.Now().Add(s.Config.Checkout.ReservationTTL),
+	}
+
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		cart, err := repos.Cart.FindCartItems(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if len(cart) == 0 {
+			return domain.ErrCartEmpty
+		}
+
+		addresses, err := repos.User.FindAddresses(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		address, ok := domain.DefaultShippingAddress(addresses)
+		if !ok {
+			return domain.ErrAddressRequired
+		}
+
+		zones, err := repos.Shipping.FindZones(ctx)
+		if err != nil {
+			return err
+		}
+
+		quotes, err := quoteShipping(zones, cart, address, rates, currency, s.Config.Shipping.VolumetricDivisor)
+		if err != nil {
+			return err
+		}
+
+		var shipping *domain.ShippingQuote
+		for i := range quotes {
+			if quotes[i].Method.ID == input.ShippingMethodId {
+				shipping = &quotes[i]
+			}
+		}
+
+		if shipping == nil {
+			return domain.ErrInvalidShipping
+		}
+
+		// reserve in product and variant order so two checkouts sharing
+		// products lock the rows in the same order and can't deadlock
+		sort.Slice(cart, func(i, j int) bool {
+			if cart[i].ProductID != cart[j].ProductID {
+				return cart[i].ProductID < cart[j].ProductID
+			}
+			return variantOrder(cart[i].VariantID) < variantOrder(cart[j].VariantID)
+		})
+
+		classes := map[uint]string{}
+		for _, item := range cart {
+			price, err := rates.Convert(item.Price(), order.Amount.Currency)
+			if err != nil {
+				return domain.ErrCurrencyNotCharged.Wrap(err)
+			}
+
+			class, err := taxClassOf(ctx, repos, classes, item.Product.CategoryId)
+			if err != nil {
+				return err
+			}
+
+			orderItem := domain.OrderItem{
+				ProductID: item.ProductID,
+				SellerID:  uint(item.Product.UserId),
+				Name:      item.Product.Name,
+				ImageUrl:  item.Product.ImageUrl,
+				Price:     price,
+				Qty:       item.Qty,
+				TaxClass:  class,
+				Tax:       money.New(0, price.Currency),
+				Discount:  money.New(0, price.Currency),
+			}
+
+			if item.Variant != nil {
+				if !item.Variant.Active {
+					return domain.ErrVariantUnavailable
+				}
+
+				orderItem.VariantID = item.VariantID
+				orderItem.Sku = item.Variant.Sku
+				orderItem.VariantName = item.Variant.Label(item.Product.Options)
+				if item.Variant.ImageUrl != "" {
+					orderItem.ImageUrl = item.Variant.ImageUrl
+				}
+			} else if item.Product.HasVariants() {
+				// options were added after the item was put in the cart
+				return domain.ErrVariantRequired
+			}
+
+			order.Items = append(order.Items, orderItem)
+			line, err := orderItem.Price.TryMul(int64(item.Qty))
+			if err != nil {
+				return err
+			}
+			if order.Amount, err = order.Amount.TryAdd(line); err != nil {
+				return err
+			}
+		}
+
+		coupon, err := repos.Coupon.FindCartCoupon(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if coupon != nil {
+			if err := redeemCoupon(ctx, repos, *coupon, userId); err != nil {
+				return err
+			}
+
+			discount, err := cartDiscount(*coupon, cart, shipping.Cost, rates, currency)
+			if err != nil {
+				return err
+			}
+
+			for i := range order.Items {
+				order.Items[i].Discount = discount.Items[i]
+			}
+
+			order.CouponID = &coupon.ID
+			order.CouponCode = coupon.Code
+			order.Discount = discount.Total()
+			if order.Amount, err = order.Amount.TrySub(order.Discount); err != nil {
+				return err
+			}
+		}
+
+		if err := s.applyTax(ctx, order, address); err != nil {
+			return err
+		}
+
+		order.ShippingMethodID = &shipping.Method.ID
+		order.ShippingMethod = shipping.Method.Name
+		order.Shipping = shipping.Cost
+		if order.Amount, err = order.Amount.TryAdd(shipping.Cost); err != nil {
+			return err
+		}
+
+		for _, sellerId := range order.Sellers() {
+			order.Fulfilments = append(order.Fulfilments, domain.Fulfilment{SellerID: sellerId, Status: domain.FulfilmentUnfulfilled})
+		}
+
+		if err := repos.Order.CreateOrder(ctx, order); err != nil {
+			return err
+		}
+
+		if order.CouponID != nil {
+			err := repos.Coupon.CreateRedemption(ctx, &domain.CouponRedemption{
+				CouponID: *order.CouponID,
+				UserID:   userId,
+				OrderID:  order.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, item := range cart {
+			ok, err := repos.Catalog.ReserveStock(ctx, item.ProductID, item.VariantID, item.Qty)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return domain.ErrOutOfStock
+			}
+
+			err = repos.Order.CreateReservation(ctx, &domain.StockReservation{
+				OrderID:   order.ID,
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Qty:       item.Qty,
+				Status:    domain.ReservationActive,
+				ExpiresAt: order.ExpiresAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Coupon.DeleteCartCoupon(ctx, userId); err != nil {
+			return err
+		}
+
+		return repos.Cart.ClearCart(ctx, userId)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payment, err := s.Payments.CreatePayment(ctx, payments.Request{
+		Reference: order.Reference,
+		Amount:    order.Amount,
+	})
+
+	if err != nil {
+		metrics.PaymentFailures.Inc()
+		if _, closeErr := s.closeOrder(ctx, order, domain.OrderCancelled); closeErr != nil {
+			slog.ErrorContext(ctx, "release stock of failed checkout failed", "order_id", order.ID, "error", closeErr)
+		}
+
+		return nil, nil, domain.ErrPaymentFailed.Wrap(err)
+	}
+
+	// events are matched to the order by reference, so a missing payment id
+	// only means we can't cancel the payment if the order expires
+	order.PaymentID = payment.ID
+	if err := s.Repo.SetPaymentId(ctx, order.ID, payment.ID); err != nil {
+		slog.ErrorContext(ctx, "save payment id failed", "order_id", order.ID, "error", err)
+	}
+
+	metrics.OrdersPlaced.Inc()
+	s.publish(ctx, *order, domain.EventOrderCreated)
+
+	return order, payment, nil
+}
+
+func (s OrderService) GetOrders(ctx context.Context, userId uint) ([]*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrders")
+	defer span.End()
+
+	return s.Repo.FindOrders(ctx, userId)
+}
+
+func (s OrderService) GetOrder(ctx context.Context, id uint, userId uint) (*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrder")
+	defer span.End()
+
+	return s.Repo.FindOrderById(ctx, id, userId)
+}
+
+// Cancel cancels an order that has not been paid yet and releases its stock.
+func (s OrderService) Cancel(ctx context.Context, id uint, userId uint) (*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.Cancel")
+	defer span.End()
+
+	order, err := s.Repo.FindOrderById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := s.closeOrder(ctx, order, domain.OrderCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	if !closed {
+		return nil, domain.ErrOrderNotPending
+	}
+
+	s.cancelPayment(ctx, order)
+	s.publish(ctx, *order, domain.EventOrderCancelled)
+
+	return order, nil
+}
+
+// HandlePaymentEvent applies a webhook sent by the payment provider. Events
+// for unknown orders and repeated events are acknowledged without effect so
+// the provider stops retrying them.
+func (s OrderService) HandlePaymentEvent(ctx context.Context, payload []byte, header http.Header) error {
+	ctx, span := tracing.Start(ctx, "OrderService.HandlePaymentEvent")
+	defer span.End()
+
+	if s.Payments == nil {
+		return domain.ErrPaymentsDisabled
+	}
+
+	event, err := s.Payments.ParseEvent(payload, header)
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		return domain.ErrInvalidSignature
+	}
+
+	if err != nil {
+		return domain.Validation("invalid_event", "malformed payment event").Wrap(err)
+	}
+
+	if event.Type == "" {
+		return nil
+	}
+
+	order, err := s.Repo.FindOrderByReference(ctx, event.Reference)
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		slog.WarnContext(ctx, "payment event for unknown order", "event_id", event.ID, "reference", event.Reference)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payments.EventSucceeded:
+		return s.markPaid(ctx, order)
+	case payments.EventFailed:
+		// the customer can retry with the same payment until the order
+		// expires
+		metrics.PaymentFailures.Inc()
+		slog.InfoContext(ctx, "payment failed", "order_id", order.ID, "event_id", event.ID)
+	}
+
+	return nil
+}
+
+// markPaid turns the order's reservations into sales. A payment that lands
+// after the order expired takes the stock again if it is still there;
+// otherwise, like a payment for a cancelled order, it is flagged for refund.
+func (s OrderService) markPaid(ctx context.Context, order *domain.Order) error {
+	var paid bool
+	err := s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		ok, err := repos.Order.UpdateOrderStatus(ctx, order.ID, domain.OrderPaid, domain.OrderPendingPayment)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			paid = true
+			if err := commitReservations(ctx, repos, order.ID); err != nil {
+				return err
+			}
+
+			return creditSellers(ctx, repos, order)
+		}
+
+		ok, err = repos.Order.UpdateOrderStatus(ctx, order.ID, domain.OrderPaid, domain.OrderExpired)
+		if err != nil || !ok {
+			return err
+		}
+
+		for _, item := range order.Items {
+			if item.VariantID == nil && item.Sku != "" {
+				// the variant was deleted since
+				return errStockGone
+			}
+
+			taken, err := repos.Catalog.TakeStock(ctx, item.ProductID, item.VariantID, item.Qty)
+			if err != nil {
+				return err
+			}
+
+			if !taken {
+				return errStockGone
+			}
+		}
+
+		// the order gave its coupon back when it expired
+		if err := reclaimCoupon(ctx, repos, order); err != nil {
+			return err
+		}
+
+		paid = true
+		return creditSellers(ctx, repos, order)
+	})
+
+	reason := err
+	if err != nil && !errors.Is(err, errStockGone) && !errors.Is(err, errCouponGone) {
+		return err
+	}
+
+	if paid {
+		order.Status = domain.OrderPaid
+		s.publish(ctx, *order, domain.EventOrderPaid)
+		return nil
+	}
+
+	refund, err := s.Repo.UpdateOrderStatus(ctx, order.ID, domain.OrderRefundPending, domain.OrderExpired, domain.OrderCancelled)
+	if err != nil {
+		return err
+	}
+
+	if refund {
+		slog.WarnContext(ctx, "order paid after it expired, refund needed", "order_id", order.ID, "payment_id", order.PaymentID, "reason", reason)
+	}
+
+	return nil
+}
+
+// ExpireOrders releases the stock of orders whose reservation ran out before
+// they were paid.
+func (s OrderService) ExpireOrders(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "OrderService.ExpireOrders")
+	defer span.End()
+
+	orders, err := s.Repo.FindExpiredOrders(ctx, time.Now(), expiredOrdersBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		closed, err := s.closeOrder(ctx, order, domain.OrderExpired)
+		if err != nil {
+			slog.ErrorContext(ctx, "expire order failed", "order_id", order.ID, "error", err)
+			continue
+		}
+
+		if closed {
+			s.cancelPayment(ctx, order)
+			s.publish(ctx, *order, domain.EventOrderCancelled)
+		}
+	}
+
+	return nil
+}
+
+// RunSweeper expires unpaid orders until the context is cancelled.
+func (s OrderService) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Checkout.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ExpireOrders(ctx); err != nil {
+				slog.ErrorContext(ctx, "expire orders failed", "error", err)
+			}
+		}
+	}
+}
+
+// closeOrder moves an order awaiting payment to status and releases its
+// stock and coupon. It reports false if the order was no longer awaiting payment, e.g.
+// because the payment arrived first.
+func (s OrderService) closeOrder(ctx context.Context, order *domain.Order, status string) (bool, error) {
+	var closed bool
+	err := s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		ok, err := repos.Order.UpdateOrderStatus(ctx, order.ID, status, domain.OrderPendingPayment)
+		if err != nil || !ok {
+			return err
+		}
+
+		closed = true
+		if err := releaseReservations(ctx, repos, order.ID); err != nil {
+			return err
+		}
+
+		return repos.Coupon.ReleaseRedemption(ctx, order.ID)
+	})
+
+	if closed && err == nil {
+		order.Status = status
+	}
+
+	return closed && err == nil, err
+}
+
+func (s OrderService) cancelPayment(ctx context.Context, order *domain.Order) {
+	if s.Payments == nil || order.PaymentID == "" {
+		return
+	}
+
+	if err := s.Payments.CancelPayment(ctx, order.PaymentID); err != nil {
+		slog.WarnContext(ctx, "cancel payment failed", "order_id", order.ID, "payment_id", order.PaymentID, "error", err)
+	}
+}
+
+// publish notifies every seller in the order about their part of it.
+func (s OrderService) publish(ctx context.Context, order domain.Order, event string) {
+	for _, sellerId := range order.Sellers() {
+		if err := s.Webhooks.Publish(ctx, sellerId, event, dto.NewOrderResponse(order.ForSeller(sellerId))); err != nil {
+			slog.ErrorContext(ctx, "publish order event failed", "order_id", order.ID, "event", event, "error", err)
+		}
+	}
+}
+
+func variantOrder(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func releaseReservations(ctx context.Context, repos repository.Repositories, orderId uint) error {
+	reservations, err := repos.Order.FindReservations(ctx, orderId, domain.ReservationActive)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		ok, err := repos.Order.UpdateReservationStatus(ctx, r.ID, domain.ReservationReleased, domain.ReservationActive)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			if err := repos.Catalog.ReleaseStock(ctx, r.ProductID, r.VariantID, r.Qty); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func commitReservations(ctx context.Context, repos repository.Repositories, orderId uint) error {
+	reservations, err := repos.Order.FindReservations(ctx, orderId, domain.ReservationActive)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		ok, err := repos.Order.UpdateReservationStatus(ctx, r.ID, domain.ReservationCommitted, domain.ReservationActive)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			if err := repos.Catalog.CommitStock(ctx, r.ProductID, r.VariantID, r.Qty); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// redeemCoupon counts the use of coupon by an order of the user, checking
+// it is still usable. It must run in the transaction creating the order.
+func redeemCoupon(ctx context.Context, repos repository.Repositories, coupon domain.Coupon, userId uint) error {
+	ok, err := repos.Coupon.RedeemCoupon(ctx, coupon.ID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return domain.ErrCouponUsedUp
+	}
+
+	// the redemption locked the coupon, so the user's other checkouts
+	// wait here until this one is committed
+	return checkCoupon(ctx, repos.Coupon, coupon, userId)
+}
+
+// reclaimCoupon counts the use of its coupon again for an order paid after
+// it expired, under the same limits as at checkout. The discount was agreed
+// when the order was placed, so the coupon's dates don't matter here.
+func reclaimCoupon(ctx context.Context, repos repository.Repositories, order *domain.Order) error {
+	if order.CouponCode == "" {
+		return nil
+	}
+
+	if order.CouponID == nil {
+		return errCouponGone
+	}
+
+	coupon, err := repos.Coupon.FindCouponById(ctx, *order.CouponID)
+	if errors.Is(err, domain.ErrCouponNotFound) {
+		return errCouponGone
+	}
+	if err != nil {
+		return err
+	}
+
+	ok, err := repos.Coupon.RedeemCoupon(ctx, coupon.ID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errCouponGone
+	}
+
+	if coupon.PerUserLimit != nil {
+		used, err := repos.Coupon.CountRedemptions(ctx, coupon.ID, order.UserID)
+		if err != nil {
+			return err
+		}
+
+		if used >= int64(*coupon.PerUserLimit) {
+			return errCouponGone
+		}
+	}
+
+	return repos.Coupon.CreateRedemption(ctx, &domain.CouponRedemption{
+		CouponID: coupon.ID,
+		UserID:   order.UserID,
+		OrderID:  order.ID,
+	})
+}
+
+// creditSellers records a sale in the ledger of each seller of the order,
+// for the total of their items.
+func creditSellers(ctx context.Context, repos repository.Repositories, order *domain.Order) error {
+	var entries []*domain.LedgerEntry
+	for _, sellerId := range order.Sellers() {
+		entries = append(entries, &domain.LedgerEntry{
+			SellerID: sellerId,
+			OrderID:  order.ID,
+			Type:     domain.LedgerSale,
+			Amount:   order.ForSeller(sellerId).Amount,
+		})
+	}
+
+	return repos.Ledger.CreateEntries(ctx, entries)
+}
+
+// applyTax works out the tax of every item, after its discount, for
+// delivery to address and adds it to the order total, unless prices already
+// include it.
+func (s OrderService) applyTax(ctx context.Context, order *domain.Order, address domain.Address) error {
+	order.TaxInclusive = s.Config.Tax.PricesIncludeTax
+	order.TaxCountry = address.Country
+	order.TaxRegion = address.Region
+	order.Tax = money.New(0, order.Amount.Currency)
+
+	if s.Tax == nil {
+		return nil
+	}
+
+	req := tax.Request{
+		Country:   address.Country,
+		Region:    address.Region,
+		Inclusive: order.TaxInclusive,
+	}
+	for _, item := range order.Items {
+		req.Lines = append(req.Lines, tax.Line{Class: item.TaxClass, Amount: item.Price.Mul(int64(item.Qty)).Sub(item.Discount)})
+	}
+
+	res, err := s.Tax.Calculate(ctx, req)
+	if err != nil {
+		return domain.ErrTaxUnavailable.Wrap(err)
+	}
+
+	if len(res.Lines) != len(order.Items) {
+		return domain.ErrTaxUnavailable.Wrap(fmt.Errorf("got tax for %d of %d items", len(res.Lines), len(order.Items)))
+	}
+
+	for i, line := range res.Lines {
+		if !line.Tax.SameCurrency(order.Tax) {
+			return domain.ErrTaxUnavailable.Wrap(fmt.Errorf("got tax in %s for an order in %s", line.Tax.Currency, order.Tax.Currency))
+		}
+
+		order.Items[i].TaxRate = line.Rate
+		order.Items[i].Tax = order.Items[i].Tax.Add(line.Tax)
+		order.Tax = order.Tax.Add(line.Tax)
+	}
+
+	if !order.TaxInclusive {
+		order.Amount = order.Amount.Add(order.Tax)
+	}
+
+	return nil
+}
+
+// taxClassOf returns the tax class of a category, remembering it in classes
+// for the other items of the cart.
+func taxClassOf(ctx context.Context, repos repository.Repositories, classes map[uint]string, categoryId uint) (string, error) {
+	if class, ok := classes[categoryId]; ok {
+		return class, nil
+	}
+
+	class := tax.StandardClass
+	if categoryId != 0 {
+		category, err := repos.Catalog.FindCategoryById(ctx, int(categoryId))
+		if err != nil && !errors.Is(err, domain.ErrCategoryNotFound) {
+			return "", err
+		}
+		if err == nil && category.TaxClass != "" {
+			class = category.TaxClass
+		}
+	}
+
+	classes[categoryId] = class
+	return class, nil
+}
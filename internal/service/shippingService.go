@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/money"
+	"strings"
+)
+
+type ShippingService struct {
+	Repo   repository.ShippingRepository
+	Cart   repository.CartRepository
+	Users  repository.UserRepository
+	Auth   helper.Auth
+	Config config.AppConfig
+}
+
+func (s ShippingService) GetZones(ctx context.Context) ([]*domain.ShippingZone, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.GetZones")
+	defer span.End()
+
+	return s.Repo.FindZones(ctx)
+}
+
+func (s ShippingService) CreateZone(ctx context.Context, input dto.ShippingZoneInput) (*domain.ShippingZone, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.CreateZone")
+	defer span.End()
+
+	areas, err := newShippingAreas(input.Areas)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := &domain.ShippingZone{Name: input.Name, Areas: areas}
+
+	if err := s.Repo.CreateZone(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (s ShippingService) UpdateZone(ctx context.Context, id uint, input dto.ShippingZoneInput) (*domain.ShippingZone, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.UpdateZone")
+	defer span.End()
+
+	zone, err := s.Repo.FindZoneById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	areas, err := newShippingAreas(input.Areas)
+	if err != nil {
+		return nil, err
+	}
+
+	zone.Name = input.Name
+	zone.Areas = areas
+
+	if err := s.Repo.UpdateZone(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (s ShippingService) DeleteZone(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "ShippingService.DeleteZone")
+	defer span.End()
+
+	return s.Repo.DeleteZone(ctx, id)
+}
+
+// CreateMethod adds a method to a zone, priced in the store currency.
+func (s ShippingService) CreateMethod(ctx context.Context, zoneId uint, input dto.ShippingMethodInput) (*domain.ShippingMethod, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.CreateMethod")
+	defer span.End()
+
+	if _, err := s.Repo.FindZoneById(ctx, zoneId); err != nil {
+		return nil, err
+	}
+
+	method := &domain.ShippingMethod{ZoneID: zoneId, Active: true}
+	s.applyMethodInput(method, input)
+
+	if err := s.Repo.CreateMethod(ctx, method); err != nil {
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s ShippingService) UpdateMethod(ctx context.Context, id uint, input dto.ShippingMethodInput) (*domain.ShippingMethod, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.UpdateMethod")
+	defer span.End()
+
+	method, err := s.Repo.FindMethodById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyMethodInput(method, input)
+
+	if err := s.Repo.UpdateMethod(ctx, method); err != nil {
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s ShippingService) DeleteMethod(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "ShippingService.DeleteMethod")
+	defer span.End()
+
+	return s.Repo.DeleteMethod(ctx, id)
+}
+
+// GetOptions quotes the ways the user's cart can be shipped to their
+// default shipping address, with costs in currency.
+func (s ShippingService) GetOptions(ctx context.Context, userId uint, rates money.Rates, currency string) ([]domain.ShippingQuote, error) {
+	ctx, span := tracing.Start(ctx, "ShippingService.GetOptions")
+	defer span.End()
+
+	cart, err := s.Cart.FindCartItems(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cart) == 0 {
+		return nil, domain.ErrCartEmpty
+	}
+
+	addresses, err := s.Users.FindAddresses(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	address, ok := domain.DefaultShippingAddress(addresses)
+	if !ok {
+		return nil, domain.ErrAddressRequired
+	}
+
+	zones, err := s.Repo.FindZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return quoteShipping(zones, cart, address, rates, currency, s.Config.Shipping.VolumetricDivisor)
+}
+
+func (s ShippingService) applyMethodInput(method *domain.ShippingMethod, input dto.ShippingMethodInput) {
+	method.Name = input.Name
+	method.Rate = input.Rate
+	method.Price = money.New(input.Price, s.Config.Payments.Currency)
+	method.PricePerKg = input.PricePerKg
+	method.FreeOver = input.FreeOver
+	if input.Active != nil {
+		method.Active = *input.Active
+	}
+}
+
+// newShippingAreas reads the areas of a zone, keeping postcode prefixes in
+// their compact form.
+func newShippingAreas(inputs []dto.ShippingAreaInput) ([]domain.ShippingArea, error) {
+	areas := make([]domain.ShippingArea, 0, len(inputs))
+	for _, a := range inputs {
+		area := domain.ShippingArea{
+			Country:      strings.ToUpper(a.Country),
+			PostcodeFrom: domain.CompactPostcode(a.PostcodeFrom),
+			PostcodeTo:   domain.CompactPostcode(a.PostcodeTo),
+		}
+
+		if area.PostcodeTo != "" && (area.PostcodeFrom == "" || area.PostcodeTo < area.PostcodeFrom) {
+			return nil, domain.ErrPostcodeRange
+		}
+
+		areas = append(areas, area)
+	}
+	return areas, nil
+}
+
+// quoteShipping prices the active methods of the zone that best covers
+// address for the cart, in currency. When zones overlap equally the oldest
+// wins. Method prices and free shipping thresholds are in their own
+// currency, which the cart is converted to for the comparison.
+func quoteShipping(zones []*domain.ShippingZone, cart []*domain.CartItem, address domain.Address, rates money.Rates, currency string, divisor int) ([]domain.ShippingQuote, error) {
+	var zone *domain.ShippingZone
+	best := 0
+	for _, z := range zones {
+		if m := z.Match(address.Country, address.PostCode); m > best {
+			zone, best = z, m
+		}
+	}
+
+	if zone == nil {
+		return nil, domain.ErrNoShipping
+	}
+
+	var weight uint
+	for _, item := range cart {
+		weight += item.Product.ShippingWeight(divisor) * item.Qty
+	}
+
+	var quotes []domain.ShippingQuote
+	for _, method := range zone.Methods {
+		if !method.Active {
+			continue
+		}
+
+		subtotal := money.New(0, method.Price.Currency)
+		for _, item := range cart {
+			line, err := rates.Convert(item.Price().Mul(int64(item.Qty)), method.Price.Currency)
+			if err != nil {
+				return nil, domain.ErrCurrencyNotCharged.Wrap(err)
+			}
+			if subtotal, err = subtotal.TryAdd(line); err != nil {
+				return nil, domain.ErrCurrencyNotCharged.Wrap(err)
+			}
+		}
+
+		cost, err := rates.Convert(method.Cost(weight, subtotal), currency)
+		if err != nil {
+			return nil, domain.ErrCurrencyNotCharged.Wrap(err)
+		}
+
+		quotes = append(quotes, domain.ShippingQuote{Zone: *zone, Method: method, Cost: cost})
+	}
+
+	if len(quotes) == 0 {
+		return nil, domain.ErrNoShipping
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/metrics"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/notifications"
+	"log/slog"
+	"time"
+)
+
+// SmsService sends text messages within the limits of config.SmsConfig, so a
+// bot cannot run up the provider bill.
+type SmsService struct {
+	Repo   repository.SmsRepository
+	Notify notifications.NotificationClient
+	Phones helper.PhoneRules
+	Config config.SmsConfig
+}
+
+func NewSmsService(repo repository.SmsRepository, notify notifications.NotificationClient, cfg config.SmsConfig) SmsService {
+	return SmsService{
+		Repo:   repo,
+		Notify: notify,
+		Phones: helper.NewPhoneRules(cfg),
+		Config: cfg,
+	}
+}
+
+// Send texts message to phone. The message is recorded as reserved before
+// the caps are checked, so concurrent sends count each other and the caps
+// cannot be overshot; at worst both are refused.
+func (s SmsService) Send(ctx context.Context, phone string, purpose string, message string) error {
+	ctx, span := tracing.Start(ctx, "SmsService.Send")
+	defer span.End()
+
+	to, err := s.Phones.Normalize(phone)
+	if err != nil {
+		metrics.SmsRejected.WithLabelValues("phone").Inc()
+		return err
+	}
+
+	sms := &domain.SmsMessage{Phone: to, Purpose: purpose, Status: domain.SmsReserved}
+	if err := s.Repo.CreateMessage(ctx, sms); err != nil {
+		return err
+	}
+
+	if err := s.checkCaps(ctx, to); err != nil {
+		s.finish(ctx, sms, domain.SmsRejected, err)
+		return err
+	}
+
+	if err := s.Notify.SendSMS(ctx, to, message); err != nil {
+		metrics.SmsSendFailures.Inc()
+		s.finish(ctx, sms, domain.SmsFailed, err)
+		return err
+	}
+
+	s.finish(ctx, sms, domain.SmsSent, nil)
+	return nil
+}
+
+func (s SmsService) checkCaps(ctx context.Context, phone string) error {
+	now := time.Now()
+
+	sent, err := s.Repo.CountMessages(ctx, phone, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent > int64(s.Config.MaxPerNumberPerDay) {
+		metrics.SmsRejected.WithLabelValues("number_cap").Inc()
+		return domain.ErrSmsLimitReached
+	}
+
+	sent, err = s.Repo.CountMessages(ctx, "", now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent > int64(s.Config.MaxPerHour) {
+		metrics.SmsRejected.WithLabelValues("global_cap").Inc()
+		slog.WarnContext(ctx, "hourly sms cap reached", "max_per_hour", s.Config.MaxPerHour)
+		return domain.ErrSmsUnavailable
+	}
+
+	return nil
+}
+
+func (s SmsService) finish(ctx context.Context, sms *domain.SmsMessage, status string, cause error) {
+	sms.Status = status
+	if cause != nil {
+		sms.Error = cause.Error()
+	}
+
+	if err := s.Repo.UpdateMessage(ctx, sms); err != nil {
+		slog.ErrorContext(ctx, "record sms status failed", "sms_id", sms.ID, "error", err)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/tax"
+	"strings"
+)
+
+// TaxService manages the local tax table. It is also the rate source of the
+// table calculator.
+type TaxService struct {
+	Repo    repository.TaxRepository
+	Catalog repository.CatalogRepository
+	Auth    helper.Auth
+	Config  config.AppConfig
+}
+
+func (s TaxService) GetRates(ctx context.Context, country string) ([]*domain.TaxRate, error) {
+	ctx, span := tracing.Start(ctx, "TaxService.GetRates")
+	defer span.End()
+
+	return s.Repo.FindTaxRates(ctx, strings.ToUpper(country))
+}
+
+func (s TaxService) CreateRate(ctx context.Context, input dto.TaxRateInput) (*domain.TaxRate, error) {
+	ctx, span := tracing.Start(ctx, "TaxService.CreateRate")
+	defer span.End()
+
+	rate := &domain.TaxRate{}
+	applyTaxRateInput(rate, input)
+
+	if err := s.Repo.CreateTaxRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s TaxService) UpdateRate(ctx context.Context, id uint, input dto.TaxRateInput) (*domain.TaxRate, error) {
+	ctx, span := tracing.Start(ctx, "TaxService.UpdateRate")
+	defer span.End()
+
+	rate, err := s.Repo.FindTaxRateById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTaxRateInput(rate, input)
+
+	if err := s.Repo.UpdateTaxRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s TaxService) DeleteRate(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "TaxService.DeleteRate")
+	defer span.End()
+
+	return s.Repo.DeleteTaxRate(ctx, id)
+}
+
+// SetCategoryClass sets the tax class the products of a category are taxed
+// under.
+func (s TaxService) SetCategoryClass(ctx context.Context, categoryId uint, input dto.TaxClassInput) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "TaxService.SetCategoryClass")
+	defer span.End()
+
+	if err := s.Catalog.SetCategoryTaxClass(ctx, categoryId, taxClass(input.TaxClass)); err != nil {
+		return nil, err
+	}
+
+	return s.Catalog.FindCategoryById(ctx, int(categoryId))
+}
+
+// TaxRates implements tax.RateSource.
+func (s TaxService) TaxRates(ctx context.Context, country string) ([]tax.Entry, error) {
+	rates, err := s.Repo.FindTaxRates(ctx, country)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]tax.Entry, 0, len(rates))
+	for _, r := range rates {
+		entries = append(entries, tax.Entry{
+			Country: r.Country,
+			Region:  r.Region,
+			Class:   r.TaxClass,
+			Rate:    r.Rate,
+		})
+	}
+
+	return entries, nil
+}
+
+// applyTaxRateInput copies the input, normalized, onto rate. Countries and
+// regions are kept in upper case and classes in lower case, so lookups
+// don't depend on how they were typed.
+func applyTaxRateInput(rate *domain.TaxRate, input dto.TaxRateInput) {
+	rate.Country = strings.ToUpper(input.Country)
+	rate.Region = strings.ToUpper(strings.TrimSpace(input.Region))
+	rate.TaxClass = taxClass(input.TaxClass)
+	rate.Name = strings.TrimSpace(input.Name)
+	rate.Rate = *input.Rate
+}
+
+func taxClass(class string) string {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if class == "" {
+		return tax.StandardClass
+	}
+	return class
+}
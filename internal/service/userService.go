@@ -1,85 +1,118 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"go-ecommerce-app/config"
 	"go-ecommerce-app/internal/domain"
 	"go-ecommerce-app/internal/dto"
 	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/metrics"
 	"go-ecommerce-app/internal/repository"
-	"go-ecommerce-app/pkg/notifications"
-	"log"
+	"go-ecommerce-app/internal/tracing"
+	"log/slog"
 	"time"
 )
 
 type UserService struct {
 	Repo   repository.UserRepository
+	Tx     repository.UnitOfWork
+	Sms    SmsService
 	Auth   helper.Auth
 	Config config.AppConfig
 }
 
-func (s UserService) findUserByEmail(email string) (*domain.User, error) {
-	user, err := s.Repo.FindUser(email)
+func (s UserService) findUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := s.Repo.FindUser(ctx, email)
 	return &user, err
 }
 
-func (s UserService) SignUp(input dto.UserSignup) (string, error) {
-	log.Printf("create user: %v\n", input)
+// normalizePhone returns phone in E.164 form if we can text it.
+func (s UserService) normalizePhone(phone string) (string, error) {
+	return helper.NewPhoneRules(s.Config.Notifications.Sms).Normalize(phone)
+}
+
+func (s UserService) SignUp(ctx context.Context, input dto.UserSignup) (string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SignUp")
+	defer span.End()
+
+	phone, err := s.normalizePhone(input.Phone)
+	if err != nil {
+		return "", err
+	}
 
 	hPassword, err := s.Auth.CreateHashPassword(input.Password)
 	if err != nil {
 		return "", err
 	}
 
-	user, err := s.Repo.CreateUser(domain.User{
+	user, err := s.Repo.CreateUser(ctx, domain.User{
 		Email:    input.Email,
 		Password: hPassword,
-		Phone:    input.Phone,
+		Phone:    phone,
 	})
 
-	//Generate token
-	log.Printf("user created: %v\n", user)
+	if err != nil {
+		return "", err
+	}
+
+	metrics.Signups.Inc()
 
+	slog.InfoContext(ctx, "user signed up", "new_user_id", user.ID)
+
+	//Generate token
 	return s.Auth.GenerateToken(user.ID, user.Email, user.UserType)
 }
 
-func (s UserService) Login(email string, password string) (string, error) {
-	log.Printf("Login attempt for user: %s\n", email)
+func (s UserService) Login(ctx context.Context, email string, password string) (string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	slog.DebugContext(ctx, "login attempt", "email", email)
+
+	user, err := s.findUserByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		metrics.LoginsFailed.Inc()
+		return "", domain.ErrInvalidCredentials
+	}
 
-	user, err := s.findUserByEmail(email)
 	if err != nil {
-		return "", errors.New("user not found")
+		return "", err
 	}
 
 	if err := s.Auth.VerifyPassword(password, user.Password); err != nil {
-		return "", errors.New("wrong password")
+		metrics.LoginsFailed.Inc()
+		return "", domain.ErrInvalidCredentials
 	}
 
 	token, err := s.Auth.GenerateToken(user.ID, user.Email, user.UserType)
 	if err != nil {
-		return "", errors.New("error while generating token")
+		return "", err
 	}
 
 	return token, nil
 }
 
-func (s UserService) isVerifiedUser(id uint) bool {
-	currentUser, err := s.Repo.FindUserById(id)
+func (s UserService) isVerifiedUser(ctx context.Context, id uint) bool {
+	currentUser, err := s.Repo.FindUserById(ctx, id)
 	return err == nil && currentUser.Verified
 }
 
-func (s UserService) GetVerificationCode(e domain.User) error {
+func (s UserService) GetVerificationCode(ctx context.Context, e domain.User) error {
+	ctx, span := tracing.Start(ctx, "UserService.GetVerificationCode")
+	defer span.End()
+
 	//check if user is verified
-	if s.isVerifiedUser(e.ID) {
-		return errors.New("user is verified")
+	if s.isVerifiedUser(ctx, e.ID) {
+		return domain.ErrAlreadyVerified
 	}
 
 	//generate verification code
 	code, err := s.Auth.GenerateCode()
 
 	if err != nil {
-		return nil
+		return err
 	}
 
 	//update user
@@ -88,120 +121,343 @@ func (s UserService) GetVerificationCode(e domain.User) error {
 		Code:   code,
 	}
 
-	_, err = s.Repo.UpdateUser(e.ID, user)
+	_, err = s.Repo.UpdateUser(ctx, e.ID, user)
 
 	if err != nil {
-		return errors.New("unable to update verification code")
+		return err
 	}
 
 	//get the user phone
-	user, _ = s.Repo.FindUserById(e.ID)
+	user, _ = s.Repo.FindUserById(ctx, e.ID)
 
 	//send sms
-	notificationClient := notifications.NewNotificationClient(s.Config)
 	message := fmt.Sprintf("Verification code: %v", code)
-	err = notificationClient.SendSMS(user.Phone, message)
+	err = s.Sms.Send(ctx, user.Phone, "verification", message)
+
+	var refused *domain.Error
+	if errors.As(err, &refused) {
+		return err
+	}
 
 	if err != nil {
-		log.Printf("Unable to send verification code: %v", err)
+		slog.ErrorContext(ctx, "send verification code failed", "error", err)
 		return errors.New("unable to send verification code")
 	}
 
+	metrics.VerificationsSent.Inc()
+
 	return nil
 }
 
-func (s UserService) VerifyCode(id uint, code int) error {
-	if s.isVerifiedUser(id) {
-		return errors.New("user is already verified")
+func (s UserService) VerifyCode(ctx context.Context, id uint, code int) error {
+	ctx, span := tracing.Start(ctx, "UserService.VerifyCode")
+	defer span.End()
+
+	if s.isVerifiedUser(ctx, id) {
+		return domain.ErrAlreadyVerified
 	}
 
-	user, err := s.Repo.FindUserById(id)
+	user, err := s.Repo.FindUserById(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if user.Code != code {
-		return errors.New("invalid code")
+		return domain.ErrInvalidCode
 	}
 
 	if !time.Now().Before(user.Expiry) {
-		return errors.New("user is expired")
+		return domain.ErrCodeExpired
 	}
 
 	updateUser := domain.User{
 		Verified: true,
 	}
 
-	_, err = s.Repo.UpdateUser(id, updateUser)
+	_, err = s.Repo.UpdateUser(ctx, id, updateUser)
 	if err != nil {
-		return errors.New("unable to update verification code")
+		return err
 	}
 
 	return nil
 }
 
-func (s UserService) CreateProfile(id uint, input any) error {
+func (s UserService) CreateProfile(ctx context.Context, id uint, input dto.ProfileInput) error {
+	ctx, span := tracing.Start(ctx, "UserService.CreateProfile")
+	defer span.End()
+
+	_, err := s.Repo.UpdateUser(ctx, id, domain.User{
+		FirstName: input.FirstName,
+		LastName:  input.LastName,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if input.AddressInput != nil {
+		if _, err := s.AddAddress(ctx, id, *input.AddressInput); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s UserService) GetProfile(id uint) (*domain.User, error) {
-	return nil, nil
+func (s UserService) GetProfile(ctx context.Context, id uint) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetProfile")
+	defer span.End()
+
+	user, err := s.Repo.FindUserWithAddresses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UpdateProfile updates the user's name. Addresses are managed through the
+// address book methods.
+func (s UserService) UpdateProfile(ctx context.Context, id uint, input dto.ProfileInput) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateProfile")
+	defer span.End()
+
+	_, err := s.Repo.UpdateUser(ctx, id, domain.User{
+		FirstName: input.FirstName,
+		LastName:  input.LastName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(ctx, id)
+}
+
+func (s UserService) GetAddresses(ctx context.Context, userId uint) ([]domain.Address, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetAddresses")
+	defer span.End()
+
+	return s.Repo.FindAddresses(ctx, userId)
+}
+
+func (s UserService) AddAddress(ctx context.Context, userId uint, input dto.AddressInput) (*domain.Address, error) {
+	ctx, span := tracing.Start(ctx, "UserService.AddAddress")
+	defer span.End()
+
+	existing, err := s.Repo.FindAddresses(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	postcode, err := helper.NormalizePostcode(input.Country, input.PostCode)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &domain.Address{
+		UserID:          userId,
+		AddressLine1:    input.AddressLine1,
+		AddressLine2:    input.AddressLine2,
+		City:            input.City,
+		PostCode:        postcode,
+		Country:         input.Country,
+		Region:          input.Region,
+		DefaultShipping: input.DefaultShipping,
+		DefaultBilling:  input.DefaultBilling,
+	}
+
+	// the first address in the book is the default for everything
+	if len(existing) == 0 {
+		address.DefaultShipping = true
+		address.DefaultBilling = true
+	}
+
+	if err := s.Repo.CreateAddress(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (s UserService) UpdateAddress(ctx context.Context, userId uint, id uint, input dto.AddressUpdateInput) (*domain.Address, error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateAddress")
+	defer span.End()
+
+	address, err := s.Repo.FindAddressById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.AddressLine1 != nil {
+		address.AddressLine1 = *input.AddressLine1
+	}
+	if input.AddressLine2 != nil {
+		address.AddressLine2 = *input.AddressLine2
+	}
+	if input.City != nil {
+		address.City = *input.City
+	}
+	if input.PostCode != nil {
+		address.PostCode = *input.PostCode
+	}
+	if input.Country != nil {
+		address.Country = *input.Country
+	}
+	if input.Region != nil {
+		address.Region = *input.Region
+	}
+	if input.DefaultShipping != nil {
+		address.DefaultShipping = *input.DefaultShipping
+	}
+	if input.DefaultBilling != nil {
+		address.DefaultBilling = *input.DefaultBilling
+	}
+
+	// a new country may write the postcode differently
+	if input.PostCode != nil || input.Country != nil {
+		if address.PostCode, err = helper.NormalizePostcode(address.Country, address.PostCode); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Repo.UpdateAddress(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
 }
 
-func (s UserService) UpdateProfile(id uint, input any) (*domain.User, error) {
-	return nil, nil
+func (s UserService) DeleteAddress(ctx context.Context, userId uint, id uint) error {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteAddress")
+	defer span.End()
+
+	address, err := s.Repo.FindAddressById(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteAddress(ctx, id, userId); err != nil {
+		return err
+	}
+
+	if !address.DefaultShipping && !address.DefaultBilling {
+		return nil
+	}
+
+	// hand the default flags over to the oldest remaining address
+	remaining, err := s.Repo.FindAddresses(ctx, userId)
+	if err != nil || len(remaining) == 0 {
+		return err
+	}
+
+	next := remaining[0]
+	next.DefaultShipping = next.DefaultShipping || address.DefaultShipping
+	next.DefaultBilling = next.DefaultBilling || address.DefaultBilling
+
+	return s.Repo.UpdateAddress(ctx, &next)
 }
 
-func (s UserService) BecomeSeller(id uint, input dto.SellerInput) (string, error) {
-	user, _ := s.Repo.FindUserById(id)
+// BecomeSeller promotes the user to seller and returns a token carrying the
+// new role, along with the seller.
+func (s UserService) BecomeSeller(ctx context.Context, id uint, input dto.SellerInput) (string, *domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.BecomeSeller")
+	defer span.End()
+
+	user, err := s.Repo.FindUserById(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
 
 	if user.UserType == domain.SELLER {
-		return "", errors.New("you have already joined seller program")
+		return "", nil, domain.ErrAlreadySeller
 	}
 
-	seller, err := s.Repo.UpdateUser(id, domain.User{
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		UserType:  domain.SELLER,
+	phone, err := s.normalizePhone(input.PhoneNumber)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// promoting the user and registering the bank account must succeed or
+	// fail together, otherwise we end up with a seller nobody can pay out
+	var seller domain.User
+	err = s.Tx.Do(ctx, func(repos repository.Repositories) error {
+		seller, err = repos.User.UpdateUser(ctx, id, domain.User{
+			FirstName: input.FirstName,
+			LastName:  input.LastName,
+			Phone:     phone,
+			UserType:  domain.SELLER,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return repos.User.CreateBankAccount(ctx, domain.BankAccount{
+			BankAccountNumber: input.BankAccountNumber,
+			SwiftCode:         input.SwiftCode,
+			PaymentType:       input.PaymentType,
+			UserID:            id,
+		})
 	})
 
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	token, err := s.Auth.GenerateToken(user.ID, user.Email, seller.UserType)
+	if err != nil {
+		return "", nil, err
+	}
 
-	err = s.Repo.CreateBankAccount(domain.BankAccount{
-		BankAccountNumber: input.BankAccountNumber,
-		SwiftCode:         input.SwiftCode,
-		PaymentType:       input.PaymentType,
-		UserID:            id,
-	})
+	return token, &seller, nil
+}
 
+// CreateAdmin creates a verified admin account. It is only reachable from
+// the command line, never through the API.
+func (s UserService) CreateAdmin(ctx context.Context, email string, password string, phone string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateAdmin")
+	defer span.End()
+
+	phone, err := s.normalizePhone(phone)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
-}
+	hPassword, err := s.Auth.CreateHashPassword(password)
+	if err != nil {
+		return nil, err
+	}
 
-func (s UserService) FindCart(id uint) ([]interface{}, error) {
-	return nil, nil
-}
+	user, err := s.Repo.CreateUser(ctx, domain.User{
+		Email:    email,
+		Password: hPassword,
+		Phone:    phone,
+		Verified: true,
+		UserType: domain.ADMIN,
+	})
 
-func (s UserService) CreateCard(input any, u domain.User) ([]interface{}, error) {
-	return nil, nil
-}
+	if err != nil {
+		return nil, err
+	}
 
-func (s UserService) CreateOrder(u domain.User) (int, error) {
-	return 0, nil
+	return &user, nil
 }
 
-func (s UserService) GetOrders(input any, u domain.User) ([]interface{}, error) {
-	return nil, nil
-}
+func (s UserService) ResetPassword(ctx context.Context, email string, password string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	user, err := s.Repo.FindUser(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hPassword, err := s.Auth.CreateHashPassword(password)
+	if err != nil {
+		return err
+	}
 
-func (s UserService) GetOrderById(id uint, uId uint) ([]interface{}, error) {
-	return nil, nil
+	_, err = s.Repo.UpdateUser(ctx, user.ID, domain.User{Password: hPassword})
+	return err
 }
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/internal/domain"
+	"go-ecommerce-app/internal/dto"
+	"go-ecommerce-app/internal/helper"
+	"go-ecommerce-app/internal/repository"
+	"go-ecommerce-app/internal/tracing"
+	"go-ecommerce-app/pkg/webhooks"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	maxWebhookAttempts   = 8
+	webhookBaseBackoff   = 30 * time.Second
+	webhookMaxBackoff    = 6 * time.Hour
+	webhookPollInterval  = 5 * time.Second
+	webhookPollBatchSize = 50
+	// webhookClaimLease outlasts sending a whole batch to endpoints that
+	// time out, so a claimed delivery isn't picked up twice
+	webhookClaimLease = 15 * time.Minute
+)
+
+type WebhookService struct {
+	Repo   repository.WebhookRepository
+	Sender webhooks.Sender
+	Auth   helper.Auth
+	Config config.AppConfig
+}
+
+type webhookEnvelope struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// validateWebhookUrl rejects urls that aren't absolute http(s) or that point
+// at our own network by address or as localhost. Names are checked again
+// when the sender connects, since they can resolve anywhere.
+func validateWebhookUrl(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return domain.ErrInvalidWebhookUrl
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return domain.ErrInvalidWebhookUrl
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil && !webhooks.PublicAddress(ip) {
+		return domain.ErrInvalidWebhookUrl
+	}
+
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return domain.Validation("webhook_events_required", "at least one event type is required")
+	}
+
+	for _, ev := range events {
+		if !isWebhookEventType(ev) {
+			return domain.Validation("invalid_webhook_event", "unknown event type: "+ev)
+		}
+	}
+
+	return nil
+}
+
+func isWebhookEventType(event string) bool {
+	for _, ev := range domain.WebhookEventTypes {
+		if ev == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookBackoff returns the delay before the next attempt once a delivery
+// has failed the given number of times.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+
+	return delay
+}
+
+func (s WebhookService) CreateEndpoint(ctx context.Context, userId uint, input dto.WebhookEndpointInput) (*domain.WebhookEndpoint, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateEndpoint")
+	defer span.End()
+
+	if err := validateWebhookUrl(input.Url); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookEvents(input.Events); err != nil {
+		return nil, err
+	}
+
+	secret, err := helper.RandomString(32)
+	if err != nil {
+		return nil, errors.New("unable to generate webhook secret")
+	}
+
+	endpoint := &domain.WebhookEndpoint{
+		UserID:      userId,
+		Url:         input.Url,
+		Description: input.Description,
+		Secret:      "whsec_" + secret,
+		Events:      input.Events,
+		Active:      true,
+	}
+
+	if err := s.Repo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s WebhookService) GetEndpoints(ctx context.Context, userId uint) ([]*domain.WebhookEndpoint, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetEndpoints")
+	defer span.End()
+
+	return s.Repo.FindEndpoints(ctx, userId)
+}
+
+func (s WebhookService) GetEndpoint(ctx context.Context, id uint, userId uint) (*domain.WebhookEndpoint, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetEndpoint")
+	defer span.End()
+
+	return s.Repo.FindEndpointById(ctx, id, userId)
+}
+
+func (s WebhookService) UpdateEndpoint(ctx context.Context, id uint, userId uint, input dto.WebhookEndpointUpdateInput) (*domain.WebhookEndpoint, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateEndpoint")
+	defer span.End()
+
+	endpoint, err := s.Repo.FindEndpointById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Url != nil {
+		if err := validateWebhookUrl(*input.Url); err != nil {
+			return nil, err
+		}
+		endpoint.Url = *input.Url
+	}
+
+	if input.Events != nil {
+		if err := validateWebhookEvents(input.Events); err != nil {
+			return nil, err
+		}
+		endpoint.Events = input.Events
+	}
+
+	if input.Description != nil {
+		endpoint.Description = *input.Description
+	}
+
+	if input.Active != nil {
+		endpoint.Active = *input.Active
+	}
+
+	if err := s.Repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s WebhookService) DeleteEndpoint(ctx context.Context, id uint, userId uint) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteEndpoint")
+	defer span.End()
+
+	return s.Repo.DeleteEndpoint(ctx, id, userId)
+}
+
+func (s WebhookService) GetDeliveries(ctx context.Context, endpointId uint, userId uint) ([]*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	if _, err := s.Repo.FindEndpointById(ctx, endpointId, userId); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindDeliveries(ctx, endpointId)
+}
+
+func (s WebhookService) GetDelivery(ctx context.Context, endpointId uint, deliveryId uint, userId uint) (*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDelivery")
+	defer span.End()
+
+	if _, err := s.Repo.FindEndpointById(ctx, endpointId, userId); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindDeliveryById(ctx, deliveryId, endpointId)
+}
+
+// Publish queues an event for every active endpoint of the seller that is
+// subscribed to it. Deliveries are sent by the background worker.
+func (s WebhookService) Publish(ctx context.Context, sellerId uint, event string, data any) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+
+	endpoints, err := s.Repo.FindSubscribedEndpoints(ctx, sellerId, event)
+	if err != nil {
+		return err
+	}
+
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhookEnvelope{Event: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range endpoints {
+		delivery := &domain.WebhookDelivery{
+			EndpointID:    e.ID,
+			EventType:     event,
+			Payload:       string(payload),
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+
+		if err := s.Repo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendTestEvent sends a webhook.test event to the endpoint right away and
+// returns the resulting delivery. Failed test events are retried like any
+// other delivery.
+func (s WebhookService) SendTestEvent(ctx context.Context, endpointId uint, userId uint) (*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.SendTestEvent")
+	defer span.End()
+
+	endpoint, err := s.Repo.FindEndpointById(ctx, endpointId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(webhookEnvelope{
+		Event:     domain.EventWebhookTest,
+		CreatedAt: time.Now().UTC(),
+		Data: map[string]any{
+			"endpoint_id": endpoint.ID,
+			"message":     "this is a test event",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the delivery is created claimed, so workers leave it to us
+	delivery := &domain.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		EventType:     domain.EventWebhookTest,
+		Payload:       string(payload),
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: time.Now().Add(webhookClaimLease),
+	}
+
+	if err := s.Repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	if err := s.attempt(ctx, endpoint, delivery); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindDeliveryById(ctx, delivery.ID, endpoint.ID)
+}
+
+// Redeliver sends an existing delivery again right away and restarts its
+// retry schedule if it fails. A delivery that is pending and not due yet may
+// be being sent by a worker, so it is refused with ErrDeliveryBusy.
+func (s WebhookService) Redeliver(ctx context.Context, endpointId uint, deliveryId uint, userId uint) (*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	endpoint, err := s.Repo.FindEndpointById(ctx, endpointId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.Repo.FindDeliveryById(ctx, deliveryId, endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.Repo.ClaimDelivery(ctx, delivery.ID, now, webhookClaimLease); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = domain.DeliveryPending
+	delivery.NextAttemptAt = now.Add(webhookClaimLease)
+	delivery.Attempts = 0
+	delivery.DeliveryAttempts = nil
+
+	if err := s.attempt(ctx, endpoint, delivery); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindDeliveryById(ctx, delivery.ID, endpoint.ID)
+}
+
+// attempt sends the delivery once, records the attempt and schedules the
+// next retry or marks the delivery as finished.
+func (s WebhookService) attempt(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.attempt")
+	defer span.End()
+
+	started := time.Now()
+	code, sendErr := s.Sender.Send(ctx, endpoint.Url, endpoint.Secret, delivery.EventType, delivery.ID, []byte(delivery.Payload))
+
+	attempt := &domain.WebhookAttempt{
+		DeliveryID:   delivery.ID,
+		ResponseCode: code,
+		DurationMs:   time.Since(started).Milliseconds(),
+	}
+
+	delivery.Attempts++
+	delivery.LastResponseCode = code
+	delivery.LastError = ""
+
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+		delivery.LastError = sendErr.Error()
+
+		if delivery.Attempts >= maxWebhookAttempts {
+			delivery.Status = domain.DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = time.Now().Add(webhookBackoff(delivery.Attempts))
+		}
+	} else {
+		now := time.Now()
+		delivery.Status = domain.DeliverySucceeded
+		delivery.DeliveredAt = &now
+	}
+
+	if err := s.Repo.CreateAttempt(ctx, attempt); err != nil {
+		return err
+	}
+
+	return s.Repo.UpdateDelivery(ctx, delivery)
+}
+
+// ProcessDueDeliveries sends every pending delivery whose next attempt is
+// due. Deliveries are claimed first, so workers running side by side send
+// each one once.
+func (s WebhookService) ProcessDueDeliveries(ctx context.Context) error {
+	deliveries, err := s.Repo.ClaimDueDeliveries(ctx, time.Now(), webhookClaimLease, webhookPollBatchSize)
+	if err != nil {
+		return err
+	}
+
+	endpoints := map[uint]*domain.WebhookEndpoint{}
+
+	for _, d := range deliveries {
+		endpoint, ok := endpoints[d.EndpointID]
+		if !ok {
+			endpoint, err = s.Repo.FindEndpoint(ctx, d.EndpointID)
+			if err != nil {
+				slog.ErrorContext(ctx, "webhook delivery failed", "delivery_id", d.ID, "error", err)
+				continue
+			}
+			endpoints[d.EndpointID] = endpoint
+		}
+
+		if !endpoint.Active {
+			d.Status = domain.DeliveryFailed
+			d.LastError = "endpoint is disabled"
+			if err := s.Repo.UpdateDelivery(ctx, d); err != nil {
+				slog.ErrorContext(ctx, "webhook delivery failed", "delivery_id", d.ID, "error", err)
+			}
+			continue
+		}
+
+		if err := s.attempt(ctx, endpoint, d); err != nil {
+			slog.ErrorContext(ctx, "webhook delivery failed", "delivery_id", d.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// RunWorker polls for due deliveries until the context is cancelled.
+func (s WebhookService) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ProcessDueDeliveries(ctx); err != nil {
+				slog.ErrorContext(ctx, "process webhook deliveries failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the trace
+// from the W3C traceparent header when the caller sent one. The span is
+// named after the route template once routing is done, and the context
+// carrying it is stored as the request's user context so services and
+// queries create child spans.
+func Middleware(ctx *fiber.Ctx) error {
+	header := http.Header{}
+	for k, v := range ctx.GetReqHeaders() {
+		header[k] = v
+	}
+	parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), propagation.HeaderCarrier(header))
+
+	method := ctx.Method()
+	spanCtx, span := Start(parent, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLPath(ctx.Path()),
+			semconv.ClientAddress(ctx.IP()),
+		),
+	)
+	defer span.End()
+
+	ctx.SetUserContext(spanCtx)
+
+	err := ctx.Next()
+
+	status := ctx.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+		span.RecordError(err)
+	}
+
+	if route := ctx.Route().Path; route != "" {
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+	}
+
+	return err
+}
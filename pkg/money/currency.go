@@ -0,0 +1,63 @@
+package money
+
+import "strings"
+
+// Currency describes how amounts in an ISO 4217 currency are written and
+// rounded. Exponent is the number of minor unit digits, e.g. 2 for EUR
+// (cents) and 0 for JPY. Computed amounts, such as conversions and
+// percentages, are rounded to a multiple of Increment minor units.
+type Currency struct {
+	Code      string
+	Exponent  int
+	Increment int64
+}
+
+// zeroDecimal and threeDecimal list the currencies whose minor unit isn't a
+// hundredth; every other known code has two decimals.
+var (
+	zeroDecimal  = "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF"
+	threeDecimal = "BHD IQD JOD KWD LYD OMR TND"
+	twoDecimal   = "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BRL BSD BTN BWP BYN BZD " +
+		"CAD CDF CHF CNY COP CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ GYD " +
+		"HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL MGA MKD MMK " +
+		"MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR RON RSD RUB " +
+		"SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD TWD TZS UAH USD " +
+		"UYU UZS VES WST XCD YER ZAR ZMW ZWL"
+
+	// cash rounding: Swiss prices end in 0 or 5 rappen
+	increments = map[string]int64{"CHF": 5}
+)
+
+var currencies = func() map[string]Currency {
+	res := map[string]Currency{}
+	for exponent, codes := range map[int]string{0: zeroDecimal, 2: twoDecimal, 3: threeDecimal} {
+		for _, code := range strings.Fields(codes) {
+			increment := increments[code]
+			if increment == 0 {
+				increment = 1
+			}
+			res[code] = Currency{Code: code, Exponent: exponent, Increment: increment}
+		}
+	}
+	return res
+}()
+
+// Lookup returns the currency with the given code, in any case.
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Known reports whether code is a supported ISO 4217 code, in any case.
+func Known(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+func mustLookup(code string) Currency {
+	c, ok := Lookup(code)
+	if !ok {
+		panic("money: unknown currency " + code)
+	}
+	return c
+}
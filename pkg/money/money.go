@@ -0,0 +1,194 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount    = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrOverflow         = errors.New("money: amount out of range")
+)
+
+// Money is an amount in integer minor units of an ISO 4217 currency, e.g.
+// {1999, "EUR"} for 19.99 euros. The zero value is an amount of nothing in
+// no currency, which adds to any other amount.
+//
+// Stored with gorm:"embedded", it maps to an amount and a currency column.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Parse reads a decimal amount such as "19.99" in the given currency. It
+// fails rather than round if s has more decimals than the currency.
+func Parse(s string, currency string) (Money, error) {
+	c, ok := Lookup(currency)
+	if !ok {
+		return Money{}, errors.New("money: unknown currency " + currency)
+	}
+
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return Money{}, ErrInvalidAmount
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(pow10(c.Exponent)))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return Money{}, ErrInvalidAmount
+	}
+
+	return Money{Amount: r.Num().Int64(), Currency: c.Code}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// SameCurrency reports whether m and o can be added. The zero value goes
+// with any currency.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == o.Currency || m == Money{} || o == Money{}
+}
+
+// Add returns m + o. It panics if the currencies differ, so callers adding
+// amounts of unknown currencies must check SameCurrency first or use TryAdd.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currencyWith(o)}
+}
+
+// Sub returns m - o, with the same rules as Add.
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.currencyWith(o)}
+}
+
+// TryAdd returns m + o, or an error rather than a panic if the currencies
+// differ. Use it for amounts that come from requests or stored data.
+func (m Money) TryAdd(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, mismatch(m, o)
+	}
+	return m.Add(o), nil
+}
+
+// TrySub returns m - o, with the same rules as TryAdd.
+func (m Money) TrySub(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, mismatch(m, o)
+	}
+	return m.Sub(o), nil
+}
+
+func mismatch(m, o Money) error {
+	return fmt.Errorf("%w %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+func (m Money) currencyWith(o Money) string {
+	if !m.SameCurrency(o) {
+		panic(mismatch(m, o))
+	}
+	if m.Currency == "" {
+		return o.Currency
+	}
+	return m.Currency
+}
+
+// Mul returns the amount qty times, e.g. the total of a line. It panics if
+// the result doesn't fit in an int64, so callers multiplying by quantities
+// from requests should use TryMul.
+func (m Money) Mul(qty int64) Money {
+	res, err := m.TryMul(qty)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// TryMul returns the amount qty times, or ErrOverflow rather than a panic
+// if the result doesn't fit in an int64.
+func (m Money) TryMul(qty int64) (Money, error) {
+	res := m.Amount * qty
+	if qty != 0 && (res/qty != m.Amount || qty == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %d x %s", ErrOverflow, qty, m)
+	}
+	return Money{Amount: res, Currency: m.Currency}, nil
+}
+
+// MulRat multiplies the amount by r, e.g. a tax rate or a discount
+// percentage, rounding the result with the currency's rules.
+func (m Money) MulRat(r *big.Rat) Money {
+	units := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), r)
+	return Money{Amount: Round(units, m.Currency), Currency: m.Currency}
+}
+
+// Round rounds an amount of minor units to the nearest multiple of the
+// currency's increment, halves to even, so repeated rounding doesn't drift
+// in either direction. Amounts in an unknown currency round to whole minor
+// units.
+func Round(units *big.Rat, currency string) int64 {
+	increment := big.NewInt(1)
+	if c, ok := Lookup(currency); ok {
+		increment.SetInt64(c.Increment)
+	}
+
+	// steps = units / increment, rounded half to even
+	steps := new(big.Rat).Quo(units, new(big.Rat).SetInt(increment))
+	q, rem := new(big.Int).QuoRem(steps.Num(), steps.Denom(), new(big.Int))
+
+	// compare 2|rem| with the denominator to find which side of the half
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch cmp := twice.Cmp(steps.Denom()); {
+	case cmp > 0, cmp == 0 && q.Bit(0) == 1:
+		q.Add(q, big.NewInt(int64(rem.Sign())))
+	}
+
+	return q.Mul(q, increment).Int64()
+}
+
+// Decimal formats the amount with the currency's decimals, e.g. "19.99".
+func (m Money) Decimal() string {
+	c, ok := Lookup(m.Currency)
+	if !ok || c.Exponent == 0 {
+		return big.NewInt(m.Amount).String()
+	}
+
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(c.Exponent)).FloatString(c.Exponent)
+}
+
+// String formats the amount for logs and messages, e.g. "19.99 EUR".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON adds the decimal form next to the minor units, so clients can
+// show amounts without knowing each currency's decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+		Decimal  string `json:"decimal"`
+	}{m.Amount, m.Currency, m.Decimal()})
+}
+
+// Sum adds the amounts, which must share a currency.
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,167 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		units    *big.Rat
+		currency string
+		want     int64
+	}{
+		{big.NewRat(5, 2), "EUR", 2},
+		{big.NewRat(7, 2), "EUR", 4},
+		{big.NewRat(-5, 2), "EUR", -2},
+		{big.NewRat(-7, 2), "EUR", -4},
+		{big.NewRat(251, 100), "EUR", 3},
+		{big.NewRat(249, 100), "EUR", 2},
+		{big.NewRat(1, 3), "EUR", 0},
+		{big.NewRat(1999, 1), "EUR", 1999},
+		{big.NewRat(5, 2), "eur", 2},
+
+		// CHF rounds to 5 rappen, halves to an even number of steps
+		{big.NewRat(12, 1), "CHF", 10},
+		{big.NewRat(13, 1), "CHF", 15},
+		{big.NewRat(25, 2), "CHF", 10},
+		{big.NewRat(35, 2), "CHF", 20},
+		{big.NewRat(-13, 1), "CHF", -15},
+		{big.NewRat(2295, 10), "CHF", 230},
+
+		// unknown currencies round to whole minor units
+		{big.NewRat(5, 2), "XYZ", 2},
+		{big.NewRat(13, 1), "XYZ", 13},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.units, tt.currency); got != tt.want {
+			t.Errorf("Round(%s, %s) = %d, want %d", tt.units, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s, currency string
+		want        Money
+	}{
+		{"19.99", "EUR", Money{1999, "EUR"}},
+		{"19.9", "EUR", Money{1990, "EUR"}},
+		{"19", "EUR", Money{1900, "EUR"}},
+		{" 19.99 ", "EUR", Money{1999, "EUR"}},
+		{"-5.00", "EUR", Money{-500, "EUR"}},
+		{"0", "EUR", Money{0, "EUR"}},
+		{"19.99", "eur", Money{1999, "EUR"}},
+		{"1000", "JPY", Money{1000, "JPY"}},
+		{"1.234", "KWD", Money{1234, "KWD"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.s, tt.currency)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, %q) = %v, %v; want %v", tt.s, tt.currency, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct{ s, currency string }{
+		{"19.999", "EUR"},
+		{"1.5", "JPY"},
+		{"1.2345", "KWD"},
+		{"1e3", "EUR"},
+		{"1/2", "EUR"},
+		{"abc", "EUR"},
+		{"", "EUR"},
+		{"99999999999999999999", "EUR"},
+	} {
+		if _, err := Parse(tt.s, tt.currency); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q, %q) = %v, want ErrInvalidAmount", tt.s, tt.currency, err)
+		}
+	}
+
+	if _, err := Parse("1", "XYZ"); err == nil {
+		t.Error("Parse in an unknown currency succeeded")
+	}
+}
+
+func TestMulRat(t *testing.T) {
+	tests := []struct {
+		m    Money
+		r    *big.Rat
+		want Money
+	}{
+		{New(1000, "EUR"), big.NewRat(23, 100), New(230, "EUR")},
+		{New(999, "EUR"), big.NewRat(23, 100), New(230, "EUR")},
+		{New(150, "EUR"), big.NewRat(1, 4), New(38, "EUR")},
+		{New(250, "EUR"), big.NewRat(1, 4), New(62, "EUR")},
+		{New(-150, "EUR"), big.NewRat(1, 4), New(-38, "EUR")},
+		{New(999, "CHF"), big.NewRat(23, 100), New(230, "CHF")},
+		{New(1010, "CHF"), big.NewRat(1, 2), New(505, "CHF")},
+		{New(1000, "JPY"), big.NewRat(8, 100), New(80, "JPY")},
+		{New(1999, "KWD"), big.NewRat(1, 10), New(200, "KWD")},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.MulRat(tt.r); got != tt.want {
+			t.Errorf("%v.MulRat(%s) = %v, want %v", tt.m, tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestTryMul(t *testing.T) {
+	tests := []struct {
+		m    Money
+		qty  int64
+		want Money
+	}{
+		{New(1999, "EUR"), 3, New(5997, "EUR")},
+		{New(1999, "EUR"), 0, New(0, "EUR")},
+		{New(0, "EUR"), math.MaxInt64, New(0, "EUR")},
+		{New(-5, "EUR"), 2, New(-10, "EUR")},
+		{New(math.MaxInt64, "EUR"), 1, New(math.MaxInt64, "EUR")},
+		{New(math.MinInt64, "EUR"), 1, New(math.MinInt64, "EUR")},
+	}
+
+	for _, tt := range tests {
+		if got, err := tt.m.TryMul(tt.qty); err != nil || got != tt.want {
+			t.Errorf("%v.TryMul(%d) = %v, %v; want %v", tt.m, tt.qty, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		amount, qty int64
+	}{
+		{math.MaxInt64/2 + 1, 2},
+		{math.MaxInt64, -2},
+		{math.MinInt64, -1},
+		{-1, math.MinInt64},
+		{1 << 32, 1 << 32},
+	} {
+		if _, err := New(tt.amount, "EUR").TryMul(tt.qty); !errors.Is(err, ErrOverflow) {
+			t.Errorf("TryMul(%d x %d) = %v, want ErrOverflow", tt.amount, tt.qty, err)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Mul overflowing didn't panic")
+		}
+	}()
+	New(math.MaxInt64, "EUR").Mul(2)
+}
+
+func TestKnown(t *testing.T) {
+	for _, code := range []string{"EUR", "eur", "Jpy", "KWD"} {
+		if !Known(code) {
+			t.Errorf("Known(%q) = false", code)
+		}
+	}
+
+	for _, code := range []string{"", "XYZ", "EURO"} {
+		if Known(code) {
+			t.Errorf("Known(%q) = true", code)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// rateDecimals is the precision rates are stored and shown with.
+const rateDecimals = 10
+
+var (
+	ErrInvalidRate = errors.New("invalid exchange rate")
+	ErrNoRate      = errors.New("no exchange rate for currency")
+)
+
+// Rate is an exact decimal exchange rate: how many units of a currency one
+// unit of another buys. It is stored as NUMERIC and sent to clients as a
+// string, so it never goes through a float.
+type Rate struct {
+	r *big.Rat
+}
+
+// One is the rate of a currency to itself.
+var One = Rate{r: big.NewRat(1, 1)}
+
+// ParseRate reads a positive decimal such as "1.0842".
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 || strings.ContainsAny(s, "/") {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{r: r}, nil
+}
+
+func (r Rate) IsZero() bool {
+	return r.r == nil
+}
+
+// Rat returns the rate as a fraction. The zero Rate is 1.
+func (r Rate) Rat() *big.Rat {
+	if r.r == nil {
+		return One.r
+	}
+	return new(big.Rat).Set(r.r)
+}
+
+// Inverse is the rate the other way round.
+func (r Rate) Inverse() Rate {
+	return Rate{r: new(big.Rat).Inv(r.Rat())}
+}
+
+func (r Rate) String() string {
+	s := r.Rat().FloatString(rateDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts the rate as a string or a number.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return ErrInvalidRate
+	}
+
+	rate, err := ParseRate(n.String())
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.Rat().FloatString(rateDecimals), nil
+}
+
+func (r *Rate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case float64:
+		s = fmt.Sprint(v)
+	case int64:
+		s = fmt.Sprint(v)
+	case nil:
+		*r = Rate{}
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Rate", src)
+	}
+
+	rate, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
+
+// Rates converts amounts between currencies through a base currency. Each
+// rate is how many units of its currency one unit of the base buys.
+type Rates struct {
+	Base  string
+	rates map[string]Rate
+}
+
+func NewRates(base string, rates map[string]Rate) Rates {
+	r := Rates{Base: base, rates: map[string]Rate{}}
+	for code, rate := range rates {
+		r.rates[strings.ToUpper(code)] = rate
+	}
+	return r
+}
+
+// Rate is the rate from the base currency to currency, in any case.
+func (r Rates) Rate(currency string) (Rate, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == strings.ToUpper(r.Base) {
+		return One, true
+	}
+
+	rate, ok := r.rates[currency]
+	return rate, ok && Known(currency)
+}
+
+// Supports reports whether amounts can be converted to and from currency.
+func (r Rates) Supports(currency string) bool {
+	_, ok := r.Rate(currency)
+	return ok
+}
+
+// Convert returns m in another currency, rounded with that currency's
+// rules.
+func (r Rates) Convert(m Money, to string) (Money, error) {
+	to = strings.ToUpper(to)
+	if m.Currency == to {
+		return m, nil
+	}
+
+	from, ok := r.Rate(m.Currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %s", ErrNoRate, m.Currency)
+	}
+
+	target, ok := r.Rate(to)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %s", ErrNoRate, to)
+	}
+
+	return ConvertAt(m, Rate{r: new(big.Rat).Quo(target.Rat(), from.Rat())}, to), nil
+}
+
+// ConvertAt returns m in another currency at a fixed rate, e.g. one locked
+// on an order.
+func ConvertAt(m Money, rate Rate, to string) Money {
+	to = strings.ToUpper(to)
+	if m.Currency == to {
+		return m
+	}
+
+	// minor units of m -> major units -> major units of to -> minor units
+	units := new(big.Rat).SetInt64(m.Amount)
+	units.Mul(units, rate.Rat())
+	units.Mul(units, new(big.Rat).SetFrac(pow10(mustLookup(to).Exponent), pow10(mustLookup(m.Currency).Exponent)))
+
+	return Money{Amount: Round(units, to), Currency: to}
+}
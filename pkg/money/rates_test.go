@@ -0,0 +1,79 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustRate(t *testing.T, s string) Rate {
+	t.Helper()
+
+	r, err := ParseRate(s)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", s, err)
+	}
+	return r
+}
+
+func TestRatesConvert(t *testing.T) {
+	rates := NewRates("EUR", map[string]Rate{
+		"USD": mustRate(t, "1.1"),
+		"jpy": mustRate(t, "160"),
+		"KWD": mustRate(t, "0.33"),
+		"CHF": mustRate(t, "0.9375"),
+	})
+
+	tests := []struct {
+		name string
+		m    Money
+		to   string
+		want Money
+	}{
+		{"same currency", New(1000, "EUR"), "EUR", New(1000, "EUR")},
+		{"two to zero decimals", New(1000, "EUR"), "JPY", New(1600, "JPY")},
+		{"zero to two decimals", New(1600, "JPY"), "EUR", New(1000, "EUR")},
+		{"two to three decimals", New(1000, "EUR"), "KWD", New(3300, "KWD")},
+		{"three to zero decimals", New(3300, "KWD"), "JPY", New(1600, "JPY")},
+		{"through the base", New(1000, "USD"), "JPY", New(1455, "JPY")},
+		{"rounds up past the half", New(1, "JPY"), "EUR", New(1, "EUR")},
+		{"rounds the half to even", New(4, "JPY"), "EUR", New(2, "EUR")},
+		{"cash rounding", New(1000, "EUR"), "CHF", New(940, "CHF")},
+		{"lower case target", New(1000, "EUR"), "jpy", New(1600, "JPY")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.m, tt.to)
+			if err != nil || got != tt.want {
+				t.Errorf("Convert(%v, %s) = %v, %v; want %v", tt.m, tt.to, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		m  Money
+		to string
+	}{
+		{New(1000, "EUR"), "GBP"},
+		{New(1000, "GBP"), "EUR"},
+	} {
+		if _, err := rates.Convert(tt.m, tt.to); !errors.Is(err, ErrNoRate) {
+			t.Errorf("Convert(%v, %s) = %v, want ErrNoRate", tt.m, tt.to, err)
+		}
+	}
+}
+
+func TestRatesRate(t *testing.T) {
+	rates := NewRates("EUR", map[string]Rate{"USD": mustRate(t, "1.1"), "XYZ": mustRate(t, "2")})
+
+	for _, code := range []string{"EUR", "eur", "USD", "usd"} {
+		if !rates.Supports(code) {
+			t.Errorf("Supports(%q) = false", code)
+		}
+	}
+
+	// a rate alone doesn't make an unknown code a currency
+	if rates.Supports("XYZ") {
+		t.Error(`Supports("XYZ") = true`)
+	}
+}
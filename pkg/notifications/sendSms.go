@@ -1,46 +1,95 @@
 package notifications
 
 import (
+	"context"
+	"errors"
 	"github.com/twilio/twilio-go"
 	twilioApi "github.com/twilio/twilio-go/rest/api/v2010"
 	"go-ecommerce-app/config"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var ErrClientClosed = errors.New("notification client is closed")
+
 type NotificationClient interface {
-	SendSMS(phone string, message string) error
+	SendSMS(ctx context.Context, phone string, message string) error
+	// Close stops accepting new messages and waits for in-flight ones until
+	// ctx is done.
+	Close(ctx context.Context) error
 }
 
 type notificationClient struct {
 	config config.AppConfig
+	client *twilio.RestClient
+
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
 }
 
 // Twilio
 
-func (c notificationClient) SendSMS(phone string, message string) error {
-	twilioAccountSid := c.config.TwilioAccountSid
-	twilioAuthToken := c.config.TwilioAuthToken
-	twilioPhoneNumber := c.config.TwilioPhoneNumber
+func (c *notificationClient) SendSMS(ctx context.Context, phone string, message string) error {
+	_, span := otel.Tracer("go-ecommerce-app/pkg/notifications").Start(ctx, "twilio.SendSMS",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("messaging.system", "twilio")),
+	)
+	defer span.End()
 
-	client := twilio.NewRestClientWithParams(twilio.ClientParams{
-		Username: twilioAccountSid,
-		Password: twilioAuthToken,
-	})
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClientClosed
+	}
+	c.inFlight.Add(1)
+	c.mu.Unlock()
+	defer c.inFlight.Done()
 
 	params := &twilioApi.CreateMessageParams{}
 	params.SetTo(phone)
-	params.SetFrom(twilioPhoneNumber)
+	params.SetFrom(c.config.Notifications.Twilio.PhoneNumber)
 	params.SetBody(message)
 
-	_, err := client.Api.CreateMessage(params)
+	_, err := c.client.Api.CreateMessage(params)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
 	return nil
 }
 
+func (c *notificationClient) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func NewNotificationClient(config config.AppConfig) NotificationClient {
 	return &notificationClient{
 		config: config,
+		client: twilio.NewRestClientWithParams(twilio.ClientParams{
+			Username: config.Notifications.Twilio.AccountSid,
+			Password: config.Notifications.Twilio.AuthToken,
+		}),
 	}
 }
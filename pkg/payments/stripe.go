@@ -0,0 +1,195 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	stripeBaseUrl = "https://api.stripe.com/v1"
+
+	// StripeSignatureHeader carries "t=<unix time>,v1=<hex hmac-sha256>".
+	StripeSignatureHeader = "Stripe-Signature"
+
+	// signatureTolerance rejects replays of old events.
+	signatureTolerance = 5 * time.Minute
+)
+
+type stripeProvider struct {
+	client        *http.Client
+	baseUrl       string
+	secretKey     string
+	webhookSecret string
+}
+
+type stripeError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *stripeProvider) CreatePayment(ctx context.Context, req Request) (*Payment, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount.Amount, 10))
+	form.Set("currency", strings.ToLower(req.Amount.Currency))
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("automatic_payment_methods[enabled]", "true")
+
+	var intent struct {
+		ID           string `json:"id"`
+		ClientSecret string `json:"client_secret"`
+	}
+
+	// retrying checkout for the same order must not create a second intent
+	if err := p.post(ctx, "/payment_intents", form, req.Reference, &intent); err != nil {
+		return nil, err
+	}
+
+	return &Payment{ID: intent.ID, ClientSecret: intent.ClientSecret}, nil
+}
+
+func (p *stripeProvider) CancelPayment(ctx context.Context, paymentId string) error {
+	return p.post(ctx, "/payment_intents/"+url.PathEscape(paymentId)+"/cancel", url.Values{}, "", nil)
+}
+
+func (p *stripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) (err error) {
+	ctx, span := otel.Tracer("go-ecommerce-app/pkg/payments").Start(ctx, "stripe.post",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("url.path", path)),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseUrl+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(p.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e stripeError
+		json.Unmarshal(body, &e)
+		return fmt.Errorf("stripe responded with status %d: %s", resp.StatusCode, e.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+func (p *stripeProvider) ParseEvent(payload []byte, header http.Header) (*Event, error) {
+	return parseStripeEvent(p.webhookSecret, payload, header.Get(StripeSignatureHeader), time.Now())
+}
+
+// parseStripeEvent verifies and decodes an event in Stripe's format. The test
+// provider accepts the same format so local tooling works with both.
+func parseStripeEvent(secret string, payload []byte, signature string, now time.Time) (*Event, error) {
+	if err := verifySignature(secret, payload, signature, now); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID       string            `json:"id"`
+				Metadata map[string]string `json:"metadata"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		ID:        raw.ID,
+		PaymentID: raw.Data.Object.ID,
+		Reference: raw.Data.Object.Metadata["reference"],
+	}
+
+	switch raw.Type {
+	case "payment_intent.succeeded":
+		event.Type = EventSucceeded
+	case "payment_intent.payment_failed":
+		event.Type = EventFailed
+	}
+
+	return event, nil
+}
+
+func verifySignature(secret string, payload []byte, signature string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+
+	for _, part := range strings.Split(signature, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if d := now.Sub(time.Unix(ts, 0)); d > signatureTolerance || d < -signatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, s := range signatures {
+		got, err := hex.DecodeString(s)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
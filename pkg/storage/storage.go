@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"go-ecommerce-app/config"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Object is a stored file as returned by List.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage keeps files under slash separated keys such as
+// "products/12/abc.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete succeeds if the file doesn't exist.
+	Delete(ctx context.Context, key string) error
+	// List returns every file whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// URL is where clients can fetch the file.
+	URL(key string) string
+}
+
+// New returns the storage selected by cfg.
+func New(cfg config.MediaConfig) (Storage, error) {
+	switch cfg.Storage {
+	case "local":
+		return NewLocal(cfg.Local.Dir, cfg.Local.BaseUrl)
+	case "s3":
+		return NewS3(cfg.S3, &http.Client{Timeout: 30 * time.Second})
+	default:
+		return nil, errors.New("unknown media storage: " + cfg.Storage)
+	}
+}
+
+// cleanKey rejects keys that could escape the storage root.
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return "", ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+func joinUrl(base string, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
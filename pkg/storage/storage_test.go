@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	for _, key := range []string{"products/12/abc.jpg", "a.jpg", "a..b/c.jpg"} {
+		if got, err := cleanKey(key); err != nil || got != key {
+			t.Errorf("cleanKey(%q) = %q, %v; want it unchanged", key, got, err)
+		}
+	}
+
+	for _, key := range []string{
+		"",
+		"..",
+		"../secret",
+		"../../etc/passwd",
+		"products/../../secret",
+		"products/..",
+		"/etc/passwd",
+		"./a.jpg",
+		"products//a.jpg",
+		"products/./a.jpg",
+		"products/",
+		`products\..\secret`,
+	} {
+		if _, err := cleanKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("cleanKey(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
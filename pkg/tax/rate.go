@@ -0,0 +1,114 @@
+package tax
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-ecommerce-app/pkg/money"
+	"math/big"
+	"strings"
+)
+
+// rateDecimals is the precision rates are stored and shown with.
+const rateDecimals = 4
+
+var ErrInvalidRate = errors.New("tax rate must be a percentage between 0 and 100")
+
+var hundred = big.NewRat(100, 1)
+
+// Rate is a tax rate in percent, such as 23 or 5.5. It is kept as an exact
+// decimal, stored as NUMERIC and sent to clients as a string. The zero
+// value is 0%.
+type Rate struct {
+	r *big.Rat
+}
+
+// ParseRate reads a percentage between 0 and 100, e.g. "8.875".
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() < 0 || r.Cmp(hundred) > 0 || strings.ContainsAny(s, "/eE") {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{r: r}, nil
+}
+
+func (r Rate) IsZero() bool {
+	return r.r == nil || r.r.Sign() == 0
+}
+
+// Percent returns the rate as a fraction of 100.
+func (r Rate) Percent() *big.Rat {
+	if r.r == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.r)
+}
+
+// Of returns the tax on amount. With inclusive, amount already contains the
+// tax, which is then amount * rate / (100 + rate).
+func (r Rate) Of(amount money.Money, inclusive bool) money.Money {
+	base := new(big.Rat).Set(hundred)
+	if inclusive {
+		base.Add(base, r.Percent())
+	}
+
+	return amount.MulRat(new(big.Rat).Quo(r.Percent(), base))
+}
+
+func (r Rate) String() string {
+	s := r.Percent().FloatString(rateDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts the rate as a string or a number.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return ErrInvalidRate
+	}
+
+	rate, err := ParseRate(n.String())
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.Percent().FloatString(rateDecimals), nil
+}
+
+func (r *Rate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case float64:
+		s = fmt.Sprint(v)
+	case int64:
+		s = fmt.Sprint(v)
+	case nil:
+		*r = Rate{}
+		return nil
+	default:
+		return fmt.Errorf("tax: cannot scan %T into Rate", src)
+	}
+
+	rate, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
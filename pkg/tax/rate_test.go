@@ -0,0 +1,52 @@
+package tax
+
+import (
+	"go-ecommerce-app/pkg/money"
+	"testing"
+)
+
+func mustRate(t *testing.T, s string) Rate {
+	t.Helper()
+
+	r, err := ParseRate(s)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", s, err)
+	}
+	return r
+}
+
+func TestRateOf(t *testing.T) {
+	tests := []struct {
+		rate      string
+		amount    money.Money
+		inclusive bool
+		want      money.Money
+	}{
+		{"23", money.New(1000, "EUR"), false, money.New(230, "EUR")},
+		{"23", money.New(1, "EUR"), false, money.New(0, "EUR")},
+		{"8.875", money.New(1000, "USD"), false, money.New(89, "USD")},
+		{"0", money.New(1000, "EUR"), false, money.New(0, "EUR")},
+
+		// inclusive prices: the tax is extracted and rounded half to even
+		{"23", money.New(123, "EUR"), true, money.New(23, "EUR")},
+		{"23", money.New(100, "EUR"), true, money.New(19, "EUR")},
+		{"23", money.New(1000, "EUR"), true, money.New(187, "EUR")},
+		{"6", money.New(106, "EUR"), true, money.New(6, "EUR")},
+		{"20", money.New(3, "EUR"), true, money.New(0, "EUR")},
+		{"20", money.New(9, "EUR"), true, money.New(2, "EUR")},
+		{"5.5", money.New(1000, "EUR"), true, money.New(52, "EUR")},
+		{"10", money.New(1100, "JPY"), true, money.New(100, "JPY")},
+		{"8.1", money.New(1081, "CHF"), true, money.New(80, "CHF")},
+		{"0", money.New(1000, "EUR"), true, money.New(0, "EUR")},
+	}
+
+	for _, tt := range tests {
+		if got := mustRate(t, tt.rate).Of(tt.amount, tt.inclusive); got != tt.want {
+			t.Errorf("%s%% of %v (inclusive %v) = %v, want %v", tt.rate, tt.amount, tt.inclusive, got, tt.want)
+		}
+	}
+
+	if got := (Rate{}).Of(money.New(1000, "EUR"), true); !got.IsZero() {
+		t.Errorf("zero rate of 10.00 EUR = %v, want 0", got)
+	}
+}
@@ -0,0 +1,73 @@
+package tax
+
+import (
+	"context"
+	"strings"
+)
+
+// Entry is a row of the local tax table: goods of Class delivered to
+// Country are taxed at Rate. An entry with a Region only applies there and
+// takes precedence over the country-wide one.
+type Entry struct {
+	Country string
+	Region  string
+	Class   string
+	Rate    Rate
+}
+
+// RateSource loads the table entries of a country.
+type RateSource interface {
+	TaxRates(ctx context.Context, country string) ([]Entry, error)
+}
+
+type table struct {
+	source RateSource
+}
+
+// NewTable returns a calculator using the rates of source. Classes without
+// an entry for the destination are not taxed.
+func NewTable(source RateSource) Calculator {
+	return table{source: source}
+}
+
+func (t table) Calculate(ctx context.Context, req Request) (*Result, error) {
+	entries, err := t.source.TaxRates(ctx, strings.ToUpper(req.Country))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Lines: make([]LineTax, 0, len(req.Lines))}
+	for _, line := range req.Lines {
+		rate := lookup(entries, req.Region, line.Class)
+		tax := rate.Of(line.Amount, req.Inclusive)
+
+		res.Lines = append(res.Lines, LineTax{Rate: rate, Tax: tax})
+		res.Total = res.Total.Add(tax)
+	}
+
+	return res, nil
+}
+
+// lookup finds the rate of class in region, falling back to the rate for
+// the whole country.
+func lookup(entries []Entry, region string, class string) Rate {
+	if class == "" {
+		class = StandardClass
+	}
+
+	var rate Rate
+	for _, e := range entries {
+		if !strings.EqualFold(e.Class, class) {
+			continue
+		}
+
+		switch {
+		case region != "" && strings.EqualFold(e.Region, region):
+			return e.Rate
+		case e.Region == "":
+			rate = e.Rate
+		}
+	}
+
+	return rate
+}
@@ -0,0 +1,59 @@
+package tax
+
+import (
+	"context"
+	"go-ecommerce-app/pkg/money"
+	"testing"
+)
+
+// staticSource serves a fixed table, keyed by country.
+type staticSource map[string][]Entry
+
+func (s staticSource) TaxRates(ctx context.Context, country string) ([]Entry, error) {
+	return s[country], nil
+}
+
+func TestTableCalculate(t *testing.T) {
+	source := staticSource{"PT": {
+		{Country: "PT", Class: "standard", Rate: mustRate(t, "23")},
+		{Country: "PT", Class: "reduced", Rate: mustRate(t, "6")},
+		{Country: "PT", Region: "PT-20", Class: "standard", Rate: mustRate(t, "16")},
+	}}
+
+	tests := []struct {
+		name    string
+		country string
+		region  string
+		class   string
+		want    string
+	}{
+		{"country rate", "PT", "", "standard", "23"},
+		{"no class is standard", "PT", "", "", "23"},
+		{"region rate", "PT", "PT-20", "standard", "16"},
+		{"region in any case", "pt", "pt-20", "STANDARD", "16"},
+		{"region missing from the table", "PT", "PT-30", "standard", "23"},
+		{"region without its own rate for the class", "PT", "PT-20", "reduced", "6"},
+		{"class missing from the table", "PT", "", "luxury", "0"},
+		{"country missing from the table", "ES", "", "standard", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := NewTable(source).Calculate(context.Background(), Request{
+				Country: tt.country,
+				Region:  tt.region,
+				Lines:   []Line{{Class: tt.class, Amount: money.New(1000, "EUR")}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := res.Lines[0].Rate.String(); got != tt.want {
+				t.Errorf("rate = %s, want %s", got, tt.want)
+			}
+			if res.Total != res.Lines[0].Tax {
+				t.Errorf("total = %v, want the line's %v", res.Total, res.Lines[0].Tax)
+			}
+		})
+	}
+}
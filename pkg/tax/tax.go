@@ -0,0 +1,57 @@
+package tax
+
+import (
+	"context"
+	"go-ecommerce-app/config"
+	"go-ecommerce-app/pkg/money"
+)
+
+// StandardClass is the tax class of goods with no class of their own.
+const StandardClass = "standard"
+
+// Request asks for the tax on the lines of an order delivered to Country
+// (ISO 3166-1 alpha-2) and, where rates differ within it, Region. With
+// Inclusive, line amounts already include tax.
+type Request struct {
+	Country   string
+	Region    string
+	Inclusive bool
+	Lines     []Line
+}
+
+// Line is the amount of one order line, its price times the quantity, taxed
+// under Class.
+type Line struct {
+	Class  string
+	Amount money.Money
+}
+
+// LineTax is the tax on one line at Rate.
+type LineTax struct {
+	Rate Rate
+	Tax  money.Money
+}
+
+// Result holds the tax of each line, in the order of the request, and their
+// sum.
+type Result struct {
+	Lines []LineTax
+	Total money.Money
+}
+
+// Calculator works out the tax on an order. The local table is the default;
+// an external service can be plugged in by implementing this interface.
+type Calculator interface {
+	Calculate(ctx context.Context, req Request) (*Result, error)
+}
+
+// New returns the calculator selected by cfg, or nil if no tax is charged.
+// The local table reads its rates from source.
+func New(cfg config.TaxConfig, source RateSource) Calculator {
+	switch cfg.Provider {
+	case "local":
+		return NewTable(source)
+	default:
+		return nil
+	}
+}
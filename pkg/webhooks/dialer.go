@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when an endpoint resolves to an address
+// inside our own network.
+var ErrForbiddenAddress = errors.New("webhook endpoint address is not public")
+
+// reserved are ranges that aren't private by Go's definition but are still
+// not somewhere a seller's endpoint can live: "this network", carrier-grade
+// NAT (where some clouds put their metadata service), IETF protocol
+// assignments, benchmarking, the reserved class E and NAT64.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// PublicAddress reports whether ip is a public unicast address. Loopback,
+// private, link-local (which holds the 169.254.169.254 metadata service),
+// multicast and reserved addresses are not.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, p := range reserved {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// publicOnly is a net.Dialer Control that refuses to connect to addresses
+// that aren't public. It runs after name resolution, for every address
+// tried and every redirect, so a name can't be pointed inwards after the
+// endpoint was checked.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !PublicAddress(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
+
+// newClient returns an http client that only connects to public addresses
+// and ignores proxy settings, which would hide the real destination.
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
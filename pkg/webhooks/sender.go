@@ -0,0 +1,99 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+type Sender interface {
+	Send(ctx context.Context, url string, secret string, event string, deliveryId uint, payload []byte) (int, error)
+}
+
+type sender struct {
+	client *http.Client
+}
+
+// Sign returns the signature header value for a payload sent at the given
+// unix timestamp: "t=<timestamp>,v1=<hex hmac-sha256 of timestamp.payload>".
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Send posts the signed payload. The W3C trace context of ctx is sent along
+// so receivers can join the trace.
+func (s sender) Send(ctx context.Context, url string, secret string, event string, deliveryId uint, payload []byte) (status int, err error) {
+	ctx, span := otel.Tracer("go-ecommerce-app/pkg/webhooks").Start(ctx, "webhook.Send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.event", event),
+			attribute.Int64("webhook.delivery_id", int64(deliveryId)),
+		),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(deliveryId), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now().Unix(), payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// NewSender returns a Sender that gives up on an endpoint after timeout and
+// never connects to loopback, private or link-local addresses.
+func NewSender(timeout time.Duration) Sender {
+	return &sender{
+		client: newClient(timeout),
+	}
+}